@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"net/http"
+
+	"github.com/ririnto/monit-exporter/internal/monit"
+	"github.com/sirupsen/logrus"
+)
+
+// monitFetcher retrieves a parsed Monit snapshot.
+type monitFetcher interface {
+	Fetch() (monit.Monit, error)
+}
+
+// errorResponse is the JSON body returned when an API request fails.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// apiStatusHandler returns the full parsed Monit snapshot as JSON.
+func apiStatusHandler(fetcher monitFetcher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		status, err := fetcher.Fetch()
+		if err != nil {
+			logrus.Warnf("apiStatusHandler: failed to fetch Monit status: %v", err)
+			writeJSON(w, http.StatusBadGateway, errorResponse{Error: err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, status)
+	}
+}
+
+// apiServiceHandler returns a single service of the parsed Monit snapshot as JSON.
+func apiServiceHandler(fetcher monitFetcher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("name")
+		status, err := fetcher.Fetch()
+		if err != nil {
+			logrus.Warnf("apiServiceHandler: failed to fetch Monit status: %v", err)
+			writeJSON(w, http.StatusBadGateway, errorResponse{Error: err.Error()})
+			return
+		}
+		for _, service := range status.Services {
+			if service.Name == name {
+				writeJSON(w, http.StatusOK, service)
+				return
+			}
+		}
+		logrus.Debugf("apiServiceHandler: service '%s' not found", name)
+		writeJSON(w, http.StatusNotFound, errorResponse{Error: "service not found: " + name})
+	}
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ririnto/monit-exporter/internal/monit"
+)
+
+type fakeFetcher struct {
+	status monit.Monit
+	err    error
+}
+
+func (f fakeFetcher) Fetch() (monit.Monit, error) {
+	return f.status, f.err
+}
+
+func newAPITestMux(fetcher monitFetcher) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/status", apiStatusHandler(fetcher))
+	mux.HandleFunc("GET /api/v1/services/{name}", apiServiceHandler(fetcher))
+	return mux
+}
+
+func TestAPIStatusHandler(t *testing.T) {
+	fetcher := fakeFetcher{status: monit.Monit{
+		Server:   monit.Server{Localhostname: "web-1"},
+		Services: []monit.Service{{Type: 3, Name: "nginx", Status: 0x200, Monitor: 1}},
+	}}
+
+	w := httptest.NewRecorder()
+	newAPITestMux(fetcher).ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/status", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var body struct {
+		Server   map[string]any   `json:"server"`
+		Services []map[string]any `json:"services"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if body.Server["localhostname"] != "web-1" {
+		t.Errorf("Expected localhostname 'web-1', got %v", body.Server["localhostname"])
+	}
+	if len(body.Services) != 1 {
+		t.Fatalf("Expected 1 service, got %d", len(body.Services))
+	}
+	if body.Services[0]["type_name"] != "Process" {
+		t.Errorf("Expected type_name 'Process', got %v", body.Services[0]["type_name"])
+	}
+	if body.Services[0]["monitor_name"] != "monitored" {
+		t.Errorf("Expected monitor_name 'monitored', got %v", body.Services[0]["monitor_name"])
+	}
+}
+
+func TestAPIStatusHandler_FetchError(t *testing.T) {
+	w := httptest.NewRecorder()
+	newAPITestMux(fakeFetcher{err: errors.New("connection refused")}).ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/status", nil))
+	if w.Code != http.StatusBadGateway {
+		t.Errorf("Expected status 502, got %d", w.Code)
+	}
+}
+
+func TestAPIServiceHandler(t *testing.T) {
+	fetcher := fakeFetcher{status: monit.Monit{
+		Services: []monit.Service{{Type: 0, Name: "rootfs"}, {Type: 3, Name: "nginx"}},
+	}}
+	mux := newAPITestMux(fetcher)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/services/nginx", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	var service map[string]any
+	if err := json.NewDecoder(w.Body).Decode(&service); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if service["name"] != "nginx" {
+		t.Errorf("Expected service 'nginx', got %v", service["name"])
+	}
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/services/missing", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", w.Code)
+	}
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ririnto/monit-exporter/internal/check"
+	"github.com/ririnto/monit-exporter/internal/config"
+	"github.com/ririnto/monit-exporter/internal/monit"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// exitFunc terminates the process with the given code; tests replace it.
+var exitFunc = os.Exit
+
+var (
+	checkServices           []string
+	checkUnmonitoredState   string
+	checkFilesystemWarning  float64
+	checkFilesystemCritical float64
+	checkCPUWarning         float64
+	checkCPUCritical        float64
+	checkMemoryWarning      float64
+	checkMemoryCritical     float64
+	checkResponseWarning    float64
+	checkResponseCritical   float64
+)
+
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Run a one-shot Nagios/Icinga compatible check",
+	Long: "Fetch and parse the Monit status once, evaluate service states and thresholds, " +
+		"print a Nagios plugin line with performance data and exit with 0 (OK), 1 (WARNING), 2 (CRITICAL) or 3 (UNKNOWN).",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logrus.Debug("checkCmd invoked: running one-shot Monit check")
+
+		if err := config.SetLogLevel(logLevel); err != nil {
+			logrus.Errorf("Failed to set log level: %v", err)
+			return exitUnknown(cmd, fmt.Errorf("failed to set log level: %w", err))
+		}
+
+		unmonitoredState, err := check.ParseState(checkUnmonitoredState)
+		if err != nil {
+			return exitUnknown(cmd, fmt.Errorf("invalid --unmonitored-state: %w", err))
+		}
+		opts := check.Options{
+			Services:         checkServices,
+			UnmonitoredState: unmonitoredState,
+			Filesystem:       check.Threshold{Warning: checkFilesystemWarning, Critical: checkFilesystemCritical},
+			CPU:              check.Threshold{Warning: checkCPUWarning, Critical: checkCPUCritical},
+			Memory:           check.Threshold{Warning: checkMemoryWarning, Critical: checkMemoryCritical},
+			Response:         check.Threshold{Warning: checkResponseWarning, Critical: checkResponseCritical},
+		}
+
+		cfg, err := newConfig()
+		if err != nil {
+			return exitUnknown(cmd, err)
+		}
+		state := runCheck(cmd, cfg, opts)
+		logrus.Debugf("checkCmd finished with state %s", state)
+		exitFunc(int(state))
+		return nil
+	},
+}
+
+// runCheck evaluates the Monit status once and prints the plugin output line.
+func runCheck(cmd *cobra.Command, cfg *config.Config, opts check.Options) check.State {
+	parsed, err := monit.StreamMonitStatus(cfg)
+	if err == nil {
+		result := check.Evaluate(parsed, opts)
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), result.String())
+		return result.State
+	}
+	logrus.Debugf("runCheck: unable to evaluate Monit status: %v", err)
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "MONIT %s - %v\n", check.Unknown, err)
+	return check.Unknown
+}
+
+// exitUnknown reports a usage error as an UNKNOWN plugin result and exits with its code,
+// since monitoring systems read exit code 1 as WARNING.
+func exitUnknown(cmd *cobra.Command, err error) error {
+	logrus.Debugf("exitUnknown: check cannot run: %v", err)
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "MONIT %s - %v\n", check.Unknown, err)
+	exitFunc(int(check.Unknown))
+	return nil
+}
+
+func init() {
+	RootCmd.AddCommand(checkCmd)
+
+	checkCmd.SetFlagErrorFunc(exitUnknown)
+	checkCmd.Flags().StringSliceVar(&checkServices, "service", nil,
+		"Only evaluate the named services; missing services are CRITICAL (repeatable).")
+	checkCmd.Flags().StringVar(&checkUnmonitoredState, "unmonitored-state", "warning",
+		"State reported for services Monit does not monitor (ok, warning, critical, unknown).")
+	checkCmd.Flags().Float64Var(&checkFilesystemWarning, "filesystem-warning", 80,
+		"Block/inode usage percent at which filesystems are WARNING (0 disables).")
+	checkCmd.Flags().Float64Var(&checkFilesystemCritical, "filesystem-critical", 90,
+		"Block/inode usage percent at which filesystems are CRITICAL (0 disables).")
+	checkCmd.Flags().Float64Var(&checkCPUWarning, "cpu-warning", 0,
+		"System CPU usage percent at which the system is WARNING (0 disables).")
+	checkCmd.Flags().Float64Var(&checkCPUCritical, "cpu-critical", 0,
+		"System CPU usage percent at which the system is CRITICAL (0 disables).")
+	checkCmd.Flags().Float64Var(&checkMemoryWarning, "memory-warning", 0,
+		"System memory usage percent at which the system is WARNING (0 disables).")
+	checkCmd.Flags().Float64Var(&checkMemoryCritical, "memory-critical", 0,
+		"System memory usage percent at which the system is CRITICAL (0 disables).")
+	checkCmd.Flags().Float64Var(&checkResponseWarning, "response-warning", 0,
+		"Port response time in seconds at which a check is WARNING (0 disables).")
+	checkCmd.Flags().Float64Var(&checkResponseCritical, "response-critical", 0,
+		"Port response time in seconds at which a check is CRITICAL (0 disables).")
+}
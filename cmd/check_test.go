@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ririnto/monit-exporter/internal/check"
+	"github.com/ririnto/monit-exporter/internal/config"
+)
+
+func TestRunCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `<?xml version="1.0"?><monit><server/><platform/>`+
+			`<service type="0"><name>rootfs</name><monitor>1</monitor><block><percent>95.0</percent></block></service>`+
+			`</monit>`)
+	}))
+	defer server.Close()
+
+	buf := new(bytes.Buffer)
+	checkCmd.SetOut(buf)
+	state := runCheck(checkCmd, &config.Config{MonitScrapeURI: server.URL}, check.Options{
+		Filesystem: check.Threshold{Warning: 80, Critical: 90},
+	})
+
+	if state != check.Critical {
+		t.Errorf("Expected CRITICAL, got %s", state)
+	}
+	if !strings.HasPrefix(buf.String(), "MONIT CRITICAL - ") || !strings.Contains(buf.String(), "'rootfs_block'=95%;80;90;0;100") {
+		t.Errorf("Unexpected plugin output: %q", buf.String())
+	}
+}
+
+func TestRunCheck_Unknown(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "some error", http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	buf := new(bytes.Buffer)
+	checkCmd.SetOut(buf)
+	state := runCheck(checkCmd, &config.Config{MonitScrapeURI: server.URL}, check.Options{})
+
+	if state != check.Unknown {
+		t.Errorf("Expected UNKNOWN, got %s", state)
+	}
+	if !strings.HasPrefix(buf.String(), "MONIT UNKNOWN - ") {
+		t.Errorf("Unexpected plugin output: %q", buf.String())
+	}
+}
+
+func TestCheckCmd_ExitCode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `<?xml version="1.0"?><monit><server/><platform/><service type="3"><name>nginx</name><monitor>1</monitor></service></monit>`)
+	}))
+	defer server.Close()
+
+	exitCode := -1
+	previousExit, previousURI := exitFunc, monitScrapeURI
+	exitFunc = func(code int) { exitCode = code }
+	defer func() {
+		exitFunc = previousExit
+		monitScrapeURI = previousURI
+	}()
+
+	monitScrapeURI = server.URL
+	checkCmd.SetOut(new(bytes.Buffer))
+	if err := checkCmd.RunE(checkCmd, nil); err != nil {
+		t.Fatalf("checkCmd returned error: %v", err)
+	}
+	if exitCode != int(check.OK) {
+		t.Errorf("Expected exit code 0, got %d", exitCode)
+	}
+}
+
+func TestCheckCmd_UsageErrorIsUnknown(t *testing.T) {
+	exitCode := -1
+	previousExit := exitFunc
+	exitFunc = func(code int) { exitCode = code }
+	defer func() {
+		exitFunc = previousExit
+		checkUnmonitoredState, logLevel, monitHeaders = "warning", "info", nil
+	}()
+
+	for name, set := range map[string]func(){
+		"unmonitored-state": func() { checkUnmonitoredState = "sometimes" },
+		"log-level":         func() { logLevel = "loud" },
+		"monit-header":      func() { monitHeaders = []string{"invalid"} },
+	} {
+		checkUnmonitoredState, logLevel, monitHeaders = "warning", "info", nil
+		set()
+		exitCode = -1
+		out := new(bytes.Buffer)
+		checkCmd.SetOut(out)
+		if err := checkCmd.RunE(checkCmd, nil); err != nil {
+			t.Fatalf("%s: checkCmd returned error: %v", name, err)
+		}
+		if exitCode != int(check.Unknown) || !strings.HasPrefix(out.String(), "MONIT UNKNOWN - ") {
+			t.Errorf("%s: Expected UNKNOWN with exit code 3, got %d: %q", name, exitCode, out.String())
+		}
+	}
+
+	exitCode = -1
+	checkCmd.SetOut(new(bytes.Buffer))
+	if err := checkCmd.FlagErrorFunc()(checkCmd, fmt.Errorf("invalid argument")); err != nil || exitCode != int(check.Unknown) {
+		t.Errorf("Expected flag errors to exit with UNKNOWN, got %d (err=%v)", exitCode, err)
+	}
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ririnto/monit-exporter/internal/monit"
+	"github.com/sirupsen/logrus"
+)
+
+// controlTokenHeader carries the control token, leaving Authorization to the web config's basic auth.
+const controlTokenHeader = "X-Monit-Exporter-Token"
+
+// serviceController performs a Monit action on the named service.
+type serviceController func(service, action string) error
+
+// controlResponse is the JSON body returned after a control action was performed.
+type controlResponse struct {
+	Service string `json:"service"`
+	Action  string `json:"action"`
+	Result  string `json:"result"`
+}
+
+// loadControlToken reads the token protecting the control API from path.
+func loadControlToken(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("unable to read control token file: %w", err)
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("control token file '%s' is empty", path)
+	}
+	return token, nil
+}
+
+// controlHandler performs service actions through controller for callers presenting token
+// in the X-Monit-Exporter-Token header. Every attempt, including rejected ones, is written
+// to the audit log together with the user authenticated by the web config, if any.
+func controlHandler(controller serviceController, token string) http.HandlerFunc {
+	expected := []byte(token)
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		service := r.PathValue("name")
+		action := r.PathValue("action")
+		user, _, _ := r.BasicAuth()
+		audit := logrus.WithFields(logrus.Fields{
+			"audit":       "control",
+			"remote_addr": r.RemoteAddr,
+			"user":        user,
+			"user_agent":  r.UserAgent(),
+			"service":     service,
+			"action":      action,
+		})
+
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get(controlTokenHeader)), expected) != 1 {
+			audit.WithField("result", "unauthorized").Warn("Rejected Monit control request")
+			writeJSON(w, http.StatusUnauthorized, errorResponse{Error: "unauthorized"})
+			return
+		}
+
+		err := controller(service, action)
+		audit = audit.WithField("duration", time.Since(start).String())
+		switch {
+		case errors.Is(err, monit.ErrInvalidAction), errors.Is(err, monit.ErrInvalidService):
+			audit.WithField("result", "invalid").Warnf("Rejected Monit control request: %v", err)
+			writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		case err != nil:
+			audit.WithField("result", "failed").Errorf("Monit control request failed: %v", err)
+			writeJSON(w, http.StatusBadGateway, errorResponse{Error: err.Error()})
+		default:
+			audit.WithField("result", "ok").Info("Performed Monit control request")
+			writeJSON(w, http.StatusOK, controlResponse{Service: service, Action: action, Result: "ok"})
+		}
+	}
+}
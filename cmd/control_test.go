@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/exporter-toolkit/web"
+	"github.com/ririnto/monit-exporter/internal/monit"
+	"github.com/sirupsen/logrus"
+)
+
+func newControlTestMux(controller serviceController) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/services/{name}/{action}", controlHandler(controller, "s3cret"))
+	return mux
+}
+
+func TestControlHandler(t *testing.T) {
+	var performed []string
+	controller := func(service, action string) error {
+		switch {
+		case action == "reboot":
+			return fmt.Errorf("%w: %s", monit.ErrInvalidAction, action)
+		case service == "broken":
+			return errors.New("monit returned non-2xx status code: 500")
+		}
+		performed = append(performed, service+":"+action)
+		return nil
+	}
+	mux := newControlTestMux(controller)
+
+	tests := []struct {
+		name     string
+		path     string
+		auth     string
+		wantCode int
+	}{
+		{name: "missing token", path: "/api/v1/services/nginx/restart", wantCode: http.StatusUnauthorized},
+		{name: "wrong token", path: "/api/v1/services/nginx/restart", auth: "nope", wantCode: http.StatusUnauthorized},
+		{name: "invalid action", path: "/api/v1/services/nginx/reboot", auth: "s3cret", wantCode: http.StatusBadRequest},
+		{name: "monit failure", path: "/api/v1/services/broken/stop", auth: "s3cret", wantCode: http.StatusBadGateway},
+		{name: "success", path: "/api/v1/services/nginx/restart", auth: "s3cret", wantCode: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", tt.path, nil)
+			if tt.auth != "" {
+				req.Header.Set(controlTokenHeader, tt.auth)
+			}
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+			if w.Code != tt.wantCode {
+				t.Errorf("Expected status %d, got %d", tt.wantCode, w.Code)
+			}
+		})
+	}
+
+	if len(performed) != 1 || performed[0] != "nginx:restart" {
+		t.Errorf("Expected only [nginx:restart] to be performed, got %v", performed)
+	}
+}
+
+func TestControlHandler_BehindBasicAuth(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "web-config.yml")
+	// The hash is bcrypt("hunter2").
+	webConfig := "basic_auth_users:\n  admin: $2a$04$LTH1/Xk.2yUffDHbGulUZOrLdN5PHxLW3ODj967SZtSuKznyw3gy.\n"
+	if err := os.WriteFile(configPath, []byte(webConfig), 0o600); err != nil {
+		t.Fatalf("Failed to write web config: %v", err)
+	}
+
+	var logs bytes.Buffer
+	logrus.SetOutput(&logs)
+	defer logrus.SetOutput(os.Stderr)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	server := &http.Server{Handler: newControlTestMux(func(service, action string) error { return nil })}
+	systemdSocket := false
+	flags := &web.FlagConfig{WebSystemdSocket: &systemdSocket, WebConfigFile: &configPath}
+	go func() { _ = web.Serve(listener, server, flags, newSlogLogger()) }()
+	defer func() { _ = server.Close() }()
+
+	url := "http://" + listener.Addr().String() + "/api/v1/services/nginx/restart"
+	tests := []struct {
+		name     string
+		user     string
+		token    string
+		wantCode int
+	}{
+		{name: "missing basic auth", token: "s3cret", wantCode: http.StatusUnauthorized},
+		{name: "missing token", user: "admin", wantCode: http.StatusUnauthorized},
+		{name: "basic auth and token", user: "admin", token: "s3cret", wantCode: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("POST", url, nil)
+			if err != nil {
+				t.Fatalf("Failed to create request: %v", err)
+			}
+			if tt.user != "" {
+				req.SetBasicAuth(tt.user, "hunter2")
+			}
+			if tt.token != "" {
+				req.Header.Set(controlTokenHeader, tt.token)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Request failed: %v", err)
+			}
+			_ = resp.Body.Close()
+			if resp.StatusCode != tt.wantCode {
+				t.Errorf("Expected status %d, got %d", tt.wantCode, resp.StatusCode)
+			}
+		})
+	}
+
+	if !strings.Contains(logs.String(), "user=admin") {
+		t.Errorf("Expected the audit log to record user=admin, got:\n%s", logs.String())
+	}
+}
+
+func TestLoadControlToken(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "token")
+	if err := os.WriteFile(path, []byte("  s3cret\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write token file: %v", err)
+	}
+	token, err := loadControlToken(path)
+	if err != nil {
+		t.Fatalf("loadControlToken failed: %v", err)
+	}
+	if token != "s3cret" {
+		t.Errorf("Expected token 's3cret', got '%s'", token)
+	}
+
+	empty := filepath.Join(dir, "empty")
+	if err := os.WriteFile(empty, []byte("\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write token file: %v", err)
+	}
+	if _, err := loadControlToken(empty); err == nil {
+		t.Error("Expected an error for an empty token file, got nil")
+	}
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"html/template"
+	"net/http"
+
+	_ "embed"
+
+	"github.com/sirupsen/logrus"
+)
+
+//go:embed static/index.html
+var embeddedDashboard string
+
+// dashboardTemplate renders the embedded status dashboard.
+var dashboardTemplate = template.Must(template.New("dashboard").Parse(embeddedDashboard))
+
+// dashboardData holds the values substituted into the dashboard template.
+type dashboardData struct {
+	MetricsPath string
+	StatusPath  string
+}
+
+// dashboardHandler serves the status dashboard, which loads the Monit snapshot from the JSON API.
+func dashboardHandler(metricsPath string) http.HandlerFunc {
+	data := dashboardData{
+		MetricsPath: metricsPath,
+		StatusPath:  "api/v1/status",
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		logrus.Debugf("Root path request received from %s", r.RemoteAddr)
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := dashboardTemplate.Execute(w, data); err != nil {
+			logrus.Errorf("dashboardHandler: failed to render dashboard: %v", err)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDashboardHandler(t *testing.T) {
+	w := httptest.NewRecorder()
+	dashboardHandler("/custom-metrics")(w, httptest.NewRequest("GET", "/", nil))
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+	if contentType := w.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "text/html") {
+		t.Errorf("Expected HTML content type, got '%s'", contentType)
+	}
+	body := w.Body.String()
+	for _, want := range []string{`href="/custom-metrics"`, `api\/v1\/status`, `id="services"`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected dashboard to contain %q", want)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ririnto/monit-exporter/internal/config"
+	"github.com/ririnto/monit-exporter/internal/exporter"
+	"github.com/ririnto/monit-exporter/internal/monit"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	dumpFormat string
+	dumpInput  string
+)
+
+var dumpCmd = &cobra.Command{
+	Use:   "dump",
+	Short: "Print the parsed Monit status as a table, JSON or YAML",
+	Long: "Fetch the Monit status (or read it from a file or stdin) and print the parsed result " +
+		"together with the metrics the exporter would produce for each service.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logrus.Debug("dumpCmd invoked: dumping parsed Monit status")
+
+		if err := config.SetLogLevel(logLevel); err != nil {
+			logrus.Errorf("Failed to set log level: %v", err)
+			return fmt.Errorf("failed to set log level: %w", err)
+		}
+
+		cfg, err := newConfig()
+		if err != nil {
+			return err
+		}
+		data, err := readMonitStatus(cfg, dumpInput, cmd.InOrStdin())
+		if err != nil {
+			return err
+		}
+		status, err := monit.ParseMonitStatus(data)
+		if err != nil {
+			return err
+		}
+		return writeDump(cmd.OutOrStdout(), status, dumpFormat)
+	},
+}
+
+// readMonitStatus returns raw Monit XML from input ("-" for stdin), or fetches it from Monit when input is empty.
+func readMonitStatus(cfg *config.Config, input string, stdin io.Reader) ([]byte, error) {
+	switch input {
+	case "":
+		return monit.FetchMonitStatus(cfg)
+	case "-":
+		logrus.Debug("readMonitStatus: reading Monit status from stdin")
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read Monit status from stdin: %w", err)
+		}
+		return data, nil
+	default:
+		logrus.Debugf("readMonitStatus: reading Monit status from %s", input)
+		data, err := os.ReadFile(input)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read Monit status: %w", err)
+		}
+		return data, nil
+	}
+}
+
+// dumpDocument builds a generic document of the snapshot with the metric names added to each service.
+func dumpDocument(status monit.Monit) (map[string]any, error) {
+	services := make([]map[string]any, 0, len(status.Services))
+	for service := range slices.Values(status.Services) {
+		data, err := json.Marshal(service)
+		if err != nil {
+			return nil, err
+		}
+		var fields map[string]any
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return nil, err
+		}
+		if fields["metrics"], err = exporter.ServiceMetricNames(service); err != nil {
+			return nil, err
+		}
+		services = append(services, fields)
+	}
+	return map[string]any{
+		"server":   status.Server,
+		"platform": status.Platform,
+		"services": services,
+	}, nil
+}
+
+// writeDump prints the snapshot in the given format.
+func writeDump(w io.Writer, status monit.Monit, format string) error {
+	switch format {
+	case "table":
+		return writeDumpTable(w, status)
+	case "json", "yaml":
+		doc, err := dumpDocument(status)
+		if err != nil {
+			return fmt.Errorf("unable to build dump: %w", err)
+		}
+		data, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return fmt.Errorf("unable to encode dump: %w", err)
+		}
+		if format == "yaml" {
+			if data, err = yaml.JSONToYAML(data); err != nil {
+				return fmt.Errorf("unable to encode dump: %w", err)
+			}
+		} else {
+			data = append(data, '\n')
+		}
+		_, err = w.Write(data)
+		return err
+	default:
+		return fmt.Errorf("unsupported format '%s' (expected table, json or yaml)", format)
+	}
+}
+
+// writeDumpTable prints the snapshot as a human-readable table.
+func writeDumpTable(w io.Writer, status monit.Monit) error {
+	_, _ = fmt.Fprintf(w, "Server:   %s (Monit %s, id %s, uptime %ds)\n",
+		status.Server.Localhostname, status.Server.Version, status.Server.ID, status.Server.Uptime)
+	_, _ = fmt.Fprintf(w, "Platform: %s %s %s (%d CPU)\n\n",
+		status.Platform.Name, status.Platform.Release, status.Platform.Machine, status.Platform.CPU)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "SERVICE\tTYPE\tSTATUS\tMONITOR\tFAILURES\tMETRICS")
+	for service := range slices.Values(status.Services) {
+		serviceType, ok := monit.ServiceTypeName(service.Type)
+		if !ok {
+			serviceType = "unknown"
+		}
+		state := "ok"
+		if service.Status != 0 {
+			state = "failed"
+		}
+		failures := strings.Join(monit.StatusFailures(service.Status), ",")
+		if failures == "" {
+			failures = "-"
+		}
+		metrics, err := exporter.ServiceMetricNames(service)
+		if err != nil {
+			return fmt.Errorf("unable to determine metrics for '%s': %w", service.Name, err)
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			service.Name, serviceType, state, monit.MonitorStateName(service.Monitor), failures, strings.Join(metrics, ","))
+	}
+	return tw.Flush()
+}
+
+func init() {
+	RootCmd.AddCommand(dumpCmd)
+
+	dumpCmd.Flags().StringVarP(&dumpFormat, "format", "o", "table", "Output format (table, json, yaml).")
+	dumpCmd.Flags().StringVarP(&dumpInput, "input", "i", "",
+		"Read Monit XML from this file ('-' for stdin) instead of fetching it from --monit-scrape-uri.")
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ririnto/monit-exporter/internal/monit"
+	"sigs.k8s.io/yaml"
+)
+
+const dumpTestXML = `<?xml version="1.0"?>
+<monit>
+  <server><version>5.26.0</version><localhostname>web-1</localhostname></server>
+  <platform><name>Linux</name></platform>
+  <service type="0"><name>rootfs</name><status>0</status><monitor>1</monitor><block><percent>42.0</percent></block></service>
+  <service type="3"><name>nginx</name><status>512</status><monitor>1</monitor></service>
+</monit>`
+
+func TestReadMonitStatus(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "status.xml")
+	if err := os.WriteFile(path, []byte(dumpTestXML), 0o600); err != nil {
+		t.Fatalf("Failed to write fixture: %v", err)
+	}
+
+	fromFile, err := readMonitStatus(nil, path, nil)
+	if err != nil || string(fromFile) != dumpTestXML {
+		t.Errorf("Expected file contents, got %q (err=%v)", fromFile, err)
+	}
+	fromStdin, err := readMonitStatus(nil, "-", strings.NewReader(dumpTestXML))
+	if err != nil || string(fromStdin) != dumpTestXML {
+		t.Errorf("Expected stdin contents, got %q (err=%v)", fromStdin, err)
+	}
+}
+
+func TestWriteDump(t *testing.T) {
+	status, err := monit.ParseMonitStatus([]byte(dumpTestXML))
+	if err != nil {
+		t.Fatalf("ParseMonitStatus failed: %v", err)
+	}
+
+	t.Run("table", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		if err := writeDump(buf, status, "table"); err != nil {
+			t.Fatalf("writeDump failed: %v", err)
+		}
+		output := buf.String()
+		for _, want := range []string{"web-1", "nonexist", "monit_service_block_usage_percent"} {
+			if !strings.Contains(output, want) {
+				t.Errorf("Expected table to contain %q, got:\n%s", want, output)
+			}
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		if err := writeDump(buf, status, "json"); err != nil {
+			t.Fatalf("writeDump failed: %v", err)
+		}
+		var doc struct {
+			Services []struct {
+				Name    string   `json:"name"`
+				Metrics []string `json:"metrics"`
+			} `json:"services"`
+		}
+		if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+			t.Fatalf("Invalid JSON output: %v", err)
+		}
+		if len(doc.Services) != 2 || doc.Services[1].Name != "nginx" {
+			t.Fatalf("Expected two services, got %+v", doc.Services)
+		}
+		if !slices.Equal(doc.Services[1].Metrics, []string{"monit_exporter_service_check", "monit_service_failure"}) {
+			t.Errorf("Expected only the service check and failure metrics for nginx, got %v", doc.Services[1].Metrics)
+		}
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		if err := writeDump(buf, status, "yaml"); err != nil {
+			t.Fatalf("writeDump failed: %v", err)
+		}
+		var doc map[string]any
+		if err := yaml.Unmarshal(buf.Bytes(), &doc); err != nil {
+			t.Fatalf("Invalid YAML output: %v", err)
+		}
+		if !strings.Contains(buf.String(), "localhostname: web-1") {
+			t.Errorf("Expected YAML to contain the server name, got:\n%s", buf.String())
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		if err := writeDump(new(bytes.Buffer), status, "xml"); err == nil {
+			t.Error("Expected an error for an unsupported format, got nil")
+		}
+	})
+}
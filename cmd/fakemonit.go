@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ririnto/monit-exporter/internal/config"
+	"github.com/ririnto/monit-exporter/internal/monittest"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	fakeMonitAddress     string
+	fakeMonitOptions     monittest.Options
+	fakeMonitTLS         bool
+	fakeMonitTLSCertFile string
+	fakeMonitTLSKeyFile  string
+)
+
+var fakeMonitCmd = &cobra.Command{
+	Use:   "fake-monit",
+	Short: "Run a simulated Monit httpd for tests and demos",
+	Long: "Serve realistic Monit status XML covering every service type at /_status, with optional " +
+		"rotating failures, basic auth, TLS, slow and failed responses, so the exporter can be load-tested " +
+		"and dashboards built without a real Monit.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logrus.Debug("fakeMonitCmd invoked: starting simulated Monit")
+
+		if err := config.SetLogLevel(logLevel); err != nil {
+			logrus.Errorf("Failed to set log level: %v", err)
+			return fmt.Errorf("failed to set log level: %w", err)
+		}
+
+		network, address := "tcp", fakeMonitAddress
+		if socket, ok := strings.CutPrefix(fakeMonitAddress, "unix://"); ok {
+			network, address = "unix", socket
+		}
+
+		server := &http.Server{Handler: monittest.NewSimulator(fakeMonitOptions)}
+		if fakeMonitTLS && fakeMonitTLSCertFile == "" {
+			host := "localhost"
+			if network == "tcp" {
+				var err error
+				if host, _, err = net.SplitHostPort(address); err != nil {
+					return fmt.Errorf("invalid address '%s': %w", fakeMonitAddress, err)
+				}
+			}
+			certificate, err := monittest.SelfSignedCertificate(host, "localhost")
+			if err != nil {
+				return err
+			}
+			server.TLSConfig = &tls.Config{Certificates: []tls.Certificate{certificate}}
+		}
+
+		listener, err := net.Listen(network, address)
+		if err != nil {
+			return fmt.Errorf("failed to listen on %s: %w", fakeMonitAddress, err)
+		}
+
+		ctx, stop := signalContext()
+		defer stop()
+		go func() {
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := server.Shutdown(shutdownCtx); err != nil {
+				logrus.Errorf("Graceful shutdown failed: %v", err)
+			}
+		}()
+
+		if fakeMonitTLS || fakeMonitTLSCertFile != "" {
+			logrus.Infof("Starting fake Monit on https://%s/_status?format=xml", fakeMonitAddress)
+			err = server.ServeTLS(listener, fakeMonitTLSCertFile, fakeMonitTLSKeyFile)
+		} else {
+			statusURL := "http://" + fakeMonitAddress + "/_status?format=xml"
+			if network == "unix" {
+				statusURL = fakeMonitAddress + "?format=xml"
+			}
+			logrus.Infof("Starting fake Monit on %s", statusURL)
+			err = server.Serve(listener)
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("failed to start fake Monit: %w", err)
+		}
+		logrus.Info("Fake Monit stopped")
+		return nil
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(fakeMonitCmd)
+
+	flags := fakeMonitCmd.Flags()
+	flags.StringVar(&fakeMonitAddress, "address", "127.0.0.1:2812", "Address the simulated Monit httpd listens on, or unix:///path for a Unix socket.")
+	flags.StringVar(&fakeMonitOptions.Hostname, "hostname", "fake-monit", "Reported Monit host name.")
+	flags.StringVar(&fakeMonitOptions.Version, "monit-version", "5.33.0", "Reported Monit version.")
+	flags.IntVar(&fakeMonitOptions.Processes, "processes", 0, "Number of extra process services (worker-N) to report.")
+	flags.BoolVar(&fakeMonitOptions.RotateFailures, "rotate-failures", false,
+		"Report a different failing service on every status request.")
+	flags.StringVar(&fakeMonitOptions.Username, "username", "", "Require basic auth with this user name.")
+	flags.StringVar(&fakeMonitOptions.Password, "password", "", "Password required together with --username.")
+	flags.DurationVar(&fakeMonitOptions.Latency, "latency", 0, "Delay every status response by this long.")
+	flags.IntVar(&fakeMonitOptions.ErrorEvery, "error-every", 0,
+		"Answer every n-th status request with 503 Service Unavailable (0 to disable).")
+	flags.BoolVar(&fakeMonitTLS, "tls", false, "Serve HTTPS, with a self-signed certificate unless --tls-cert-file is set.")
+	flags.StringVar(&fakeMonitTLSCertFile, "tls-cert-file", "", "Certificate file for HTTPS.")
+	flags.StringVar(&fakeMonitTLSKeyFile, "tls-key-file", "", "Key file for HTTPS.")
+}
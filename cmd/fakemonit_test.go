@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFakeMonitCmd_InvalidAddress(t *testing.T) {
+	defer func() { fakeMonitAddress, fakeMonitTLS = "127.0.0.1:2812", false }()
+	fakeMonitAddress = "no-port"
+	fakeMonitTLS = true
+
+	err := fakeMonitCmd.RunE(fakeMonitCmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "invalid address") {
+		t.Errorf("Expected an invalid address error, got %v", err)
+	}
+}
+
+func TestFakeMonitCmd_Flags(t *testing.T) {
+	for _, name := range []string{"address", "rotate-failures", "username", "latency", "error-every", "tls"} {
+		if fakeMonitCmd.Flags().Lookup(name) == nil {
+			t.Errorf("Expected flag --%s", name)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ririnto/monit-exporter/internal/config"
+	"github.com/ririnto/monit-exporter/internal/exporter"
+	"github.com/ririnto/monit-exporter/internal/sink"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	forwardInterval time.Duration
+	forwardOnce     bool
+
+	influxURL         string
+	influxTokenFile   string
+	influxUser        string
+	influxPassword    string
+	influxMeasurement string
+	influxTags        []string
+	influxTimeout     time.Duration
+	influxTLS         config.TLSOptions
+
+	graphiteAddress string
+	graphitePath    string
+	graphiteTimeout time.Duration
+
+	statsdAddress string
+	statsdFlavor  string
+	statsdMetric  string
+	statsdTags    []string
+)
+
+var forwardCmd = &cobra.Command{
+	Use:   "forward",
+	Short: "Forward Monit status to InfluxDB, Graphite and StatsD sinks",
+	Long: "Poll Monit every --interval and write each parsed snapshot to the configured output sinks. " +
+		"Measurement names, tags and metric paths are Go templates over .Host, .ServiceName, .ServiceType and .Field.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logrus.Debug("forwardCmd invoked: starting sink forwarder")
+
+		if err := config.SetLogLevel(logLevel); err != nil {
+			logrus.Errorf("Failed to set log level: %v", err)
+			return fmt.Errorf("failed to set log level: %w", err)
+		}
+		if forwardInterval <= 0 {
+			return errors.New("--interval must be positive")
+		}
+		sinks, err := newSinks()
+		if err != nil {
+			return err
+		}
+		if len(sinks) == 0 {
+			return errors.New("no sink configured (set --influx-url, --graphite-address or --statsd-address)")
+		}
+
+		cfg, err := newConfig()
+		if err != nil {
+			return err
+		}
+		exp, err := exporter.NewExporter(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to create exporter: %w", err)
+		}
+
+		if forwardOnce {
+			return forwardOnceTo(context.Background(), exp, sinks)
+		}
+
+		ctx, cancel := signalContext()
+		defer cancel()
+		logrus.Infof("Forwarding Monit status to %d sinks every %s", len(sinks), forwardInterval)
+		runPeriodically(ctx, forwardInterval, func(ctx context.Context) {
+			if err := forwardOnceTo(ctx, exp, sinks); err != nil {
+				logrus.Errorf("Failed to forward Monit status: %v", err)
+			}
+		})
+		logrus.Info("Forwarder stopped")
+		return nil
+	},
+}
+
+// forwardOnceTo fetches one Monit snapshot and writes it to every sink.
+func forwardOnceTo(ctx context.Context, exp *exporter.Exporter, sinks []sink.Sink) error {
+	status, err := exp.Fetch()
+	if err != nil {
+		return fmt.Errorf("failed to fetch Monit status: %w", err)
+	}
+	return sink.WriteAll(ctx, sinks, status)
+}
+
+// newSinks builds the sinks enabled by the forward flags.
+func newSinks() ([]sink.Sink, error) {
+	var sinks []sink.Sink
+
+	if influxURL != "" {
+		measurement, err := sink.ParseNameTemplate(influxMeasurement)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --influx-measurement: %w", err)
+		}
+		pairs, err := parsePairs("--influx-tag", influxTags)
+		if err != nil {
+			return nil, err
+		}
+		tags := make(map[string]*sink.NameTemplate, len(pairs))
+		for _, pair := range pairs {
+			if tags[pair.Name], err = sink.ParseNameTemplate(pair.Value); err != nil {
+				return nil, fmt.Errorf("invalid --influx-tag %s: %w", pair.Name, err)
+			}
+		}
+		tlsConfig, err := influxTLS.ClientConfig()
+		if err != nil {
+			return nil, fmt.Errorf("invalid InfluxDB TLS configuration: %w", err)
+		}
+		influx := &sink.InfluxSink{
+			URL: influxURL,
+			HTTPClient: &http.Client{
+				Timeout:   influxTimeout,
+				Transport: &http.Transport{Proxy: http.ProxyFromEnvironment, TLSClientConfig: tlsConfig},
+			},
+			Username:    influxUser,
+			Password:    influxPassword,
+			Measurement: measurement,
+			Tags:        tags,
+		}
+		if influxTokenFile != "" {
+			data, err := os.ReadFile(influxTokenFile)
+			if err != nil {
+				return nil, fmt.Errorf("unable to read InfluxDB token file: %w", err)
+			}
+			influx.Token = strings.TrimSpace(string(data))
+		}
+		sinks = append(sinks, influx)
+	}
+
+	if graphiteAddress != "" {
+		path, err := sink.ParseNameTemplate(graphitePath)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --graphite-path: %w", err)
+		}
+		sinks = append(sinks, &sink.GraphiteSink{Address: graphiteAddress, Timeout: graphiteTimeout, Path: path})
+	}
+
+	if statsdAddress != "" {
+		if err := sink.ValidateFlavor(statsdFlavor); err != nil {
+			return nil, err
+		}
+		text := statsdMetric
+		if text == "" {
+			text = "monit.{{.Field}}"
+			if statsdFlavor == sink.FlavorStatsD {
+				text = "monit.{{.Host}}.{{.ServiceType}}.{{.ServiceName}}.{{.Field}}"
+			}
+		}
+		metric, err := sink.ParseNameTemplate(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --statsd-metric: %w", err)
+		}
+		sinks = append(sinks, &sink.StatsDSink{Address: statsdAddress, Flavor: statsdFlavor, Metric: metric, Tags: statsdTags})
+	}
+
+	return sinks, nil
+}
+
+func init() {
+	RootCmd.AddCommand(forwardCmd)
+
+	forwardCmd.Flags().DurationVar(&forwardInterval, "interval", 30*time.Second, "How often to collect Monit status and forward it.")
+	forwardCmd.Flags().BoolVar(&forwardOnce, "once", false, "Forward a single snapshot and exit.")
+
+	forwardCmd.Flags().StringVar(&influxURL, "influx-url", "",
+		"InfluxDB write URL including query, e.g. http://influx:8086/api/v2/write?org=o&bucket=b (enables the sink).")
+	forwardCmd.Flags().StringVar(&influxTokenFile, "influx-token-file", "", "File containing an InfluxDB API token.")
+	forwardCmd.Flags().StringVar(&influxUser, "influx-user", "", "Basic auth username for InfluxDB 1.x.")
+	forwardCmd.Flags().StringVar(&influxPassword, "influx-password", "", "Basic auth password for InfluxDB 1.x.")
+	forwardCmd.Flags().StringVar(&influxMeasurement, "influx-measurement", "monit", "Measurement name template.")
+	forwardCmd.Flags().StringArrayVar(&influxTags, "influx-tag",
+		[]string{"host={{.Host}}", "service_name={{.ServiceName}}", "service_type={{.ServiceType}}"},
+		"Tag as name=template (repeatable); empty values are omitted.")
+	forwardCmd.Flags().DurationVar(&influxTimeout, "influx-timeout", 10*time.Second, "Timeout for each InfluxDB write.")
+	addTLSFlags(forwardCmd.Flags(), "influx", "InfluxDB", &influxTLS)
+
+	forwardCmd.Flags().StringVar(&graphiteAddress, "graphite-address", "", "Graphite plaintext host:port (enables the sink).")
+	forwardCmd.Flags().StringVar(&graphitePath, "graphite-path", "monit.{{.Host}}.{{.ServiceType}}.{{.ServiceName}}.{{.Field}}",
+		"Metric path template; values are sanitized into single path components.")
+	forwardCmd.Flags().DurationVar(&graphiteTimeout, "graphite-timeout", 10*time.Second, "Timeout for connecting and writing to Graphite.")
+
+	forwardCmd.Flags().StringVar(&statsdAddress, "statsd-address", "", "StatsD/DogStatsD UDP host:port (enables the sink).")
+	forwardCmd.Flags().StringVar(&statsdFlavor, "statsd-flavor", sink.FlavorDogStatsD,
+		"StatsD protocol flavor: dogstatsd (tagged) or statsd (service encoded in the name).")
+	forwardCmd.Flags().StringVar(&statsdMetric, "statsd-metric", "",
+		"Gauge name template (defaults to monit.{{.Field}} for dogstatsd and a per-service path for statsd).")
+	forwardCmd.Flags().StringSliceVar(&statsdTags, "statsd-tag", nil, "Constant DogStatsD tag such as env:prod (repeatable).")
+}
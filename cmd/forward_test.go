@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestForwardCmd_Once(t *testing.T) {
+	statusPath := filepath.Join(t.TempDir(), "status.xml")
+	if err := os.WriteFile(statusPath, []byte(dumpTestXML), 0o600); err != nil {
+		t.Fatalf("Failed to write fixture: %v", err)
+	}
+
+	var influxBody string
+	influx := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		influxBody = string(body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer influx.Close()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen returned error: %v", err)
+	}
+	defer listener.Close()
+	graphiteData := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		data, _ := io.ReadAll(conn)
+		_ = conn.Close()
+		graphiteData <- string(data)
+	}()
+
+	previousURI := monitScrapeURI
+	defer func() {
+		monitScrapeURI = previousURI
+		influxURL, graphiteAddress, forwardOnce = "", "", false
+	}()
+	monitScrapeURI = "file://" + statusPath
+	influxURL = influx.URL + "/write?db=monit"
+	graphiteAddress = listener.Addr().String()
+	forwardOnce = true
+
+	if err := forwardCmd.RunE(forwardCmd, nil); err != nil {
+		t.Fatalf("forwardCmd returned error: %v", err)
+	}
+	if !strings.Contains(influxBody, "monit,host=web-1,service_name=rootfs,service_type=Filesystem status=0,monitor=1") {
+		t.Errorf("Unexpected InfluxDB body:\n%s", influxBody)
+	}
+	if data := <-graphiteData; !strings.Contains(data, "monit.web-1.Process.nginx.status 512 ") {
+		t.Errorf("Unexpected Graphite data:\n%s", data)
+	}
+}
+
+func TestForwardCmd_StatsD(t *testing.T) {
+	statusPath := filepath.Join(t.TempDir(), "status.xml")
+	if err := os.WriteFile(statusPath, []byte(dumpTestXML), 0o600); err != nil {
+		t.Fatalf("Failed to write fixture: %v", err)
+	}
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("ListenUDP returned error: %v", err)
+	}
+	defer conn.Close()
+
+	previousURI := monitScrapeURI
+	defer func() {
+		monitScrapeURI = previousURI
+		statsdAddress, statsdTags, forwardOnce = "", nil, false
+	}()
+	monitScrapeURI = "file://" + statusPath
+	statsdAddress = conn.LocalAddr().String()
+	statsdTags = []string{"env:test"}
+	forwardOnce = true
+
+	if err := forwardCmd.RunE(forwardCmd, nil); err != nil {
+		t.Fatalf("forwardCmd returned error: %v", err)
+	}
+	buf := make([]byte, 65535)
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("Expected a StatsD datagram, got %v", err)
+	}
+	if want := "monit.status:0|g|#env:test,service_name:rootfs,service_type:Filesystem,host:web-1"; !strings.Contains(string(buf[:n]), want) {
+		t.Errorf("Expected datagram to contain %q, got:\n%s", want, buf[:n])
+	}
+}
+
+func TestForwardCmd_NoSinks(t *testing.T) {
+	if err := forwardCmd.RunE(forwardCmd, nil); err == nil {
+		t.Error("Expected an error without sinks, got nil")
+	}
+}
+
+func TestNewSinks_InvalidTemplate(t *testing.T) {
+	defer func() {
+		graphiteAddress, graphitePath = "", "monit.{{.Host}}.{{.ServiceType}}.{{.ServiceName}}.{{.Field}}"
+	}()
+	graphiteAddress = "localhost:2003"
+	graphitePath = "monit.{{.Missing}}"
+
+	if _, err := newSinks(); err == nil {
+		t.Error("Expected an error for an invalid path template, got nil")
+	}
+}
+
+func TestNewSinks_InvalidStatsDFlavor(t *testing.T) {
+	defer func() { statsdAddress, statsdFlavor = "", "dogstatsd" }()
+	statsdAddress = "localhost:8125"
+	statsdFlavor = "collectd"
+
+	if _, err := newSinks(); err == nil {
+		t.Error("Expected an error for an invalid StatsD flavor, got nil")
+	}
+}
+
+func TestForwardCmd_InfluxTagWithComma(t *testing.T) {
+	defaults := influxTags
+	defer func() { influxTags = defaults }()
+	tag := `pair={{printf "%s,%s" .Host .ServiceName}}`
+	if err := forwardCmd.Flags().Set("influx-tag", tag); err != nil {
+		t.Fatalf("Failed to set --influx-tag: %v", err)
+	}
+	if len(influxTags) != 1 || influxTags[0] != tag {
+		t.Errorf("Expected a single tag value, got %v", influxTags)
+	}
+}
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/ririnto/monit-exporter/internal/config"
+	"github.com/ririnto/monit-exporter/internal/exporter"
+	"github.com/ririnto/monit-exporter/internal/grafana"
+	"github.com/ririnto/monit-exporter/internal/rules"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	generateRulesFormat     string
+	generateRulesName       string
+	generateRulesNamespace  string
+	generateRulesLabels     []string
+	generateRulesThresholds = rules.DefaultThresholds()
+
+	generateDashboardTitle string
+	generateDashboardUID   string
+)
+
+var generateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate monitoring configuration for the exporter's metrics",
+}
+
+var generateRulesCmd = &cobra.Command{
+	Use:   "rules",
+	Short: "Print Prometheus alerting and recording rules for Monit",
+	Long: "Print recording and alerting rules covering an unreachable Monit, failing services, " +
+		"filesystems running out of space or inodes, expiring certificates and stale collections, " +
+		"either as a Prometheus rule file or as a Prometheus Operator PrometheusRule resource.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logrus.Debug("generateRulesCmd invoked: generating Prometheus rules")
+
+		if err := config.SetLogLevel(logLevel); err != nil {
+			logrus.Errorf("Failed to set log level: %v", err)
+			return fmt.Errorf("failed to set log level: %w", err)
+		}
+
+		labels, err := parsePairs("--label", generateRulesLabels)
+		if err != nil {
+			return err
+		}
+		metadata := rules.Metadata{Name: generateRulesName, Namespace: generateRulesNamespace}
+		if len(labels) > 0 {
+			metadata.Labels = make(map[string]string, len(labels))
+			for _, label := range labels {
+				metadata.Labels[label.Name] = label.Value
+			}
+		}
+		return writeRules(cmd.OutOrStdout(), generateRulesThresholds, generateRulesFormat, metadata)
+	},
+}
+
+var generateDashboardCmd = &cobra.Command{
+	Use:   "dashboard",
+	Short: "Print a Grafana dashboard for the exporter's metrics",
+	Long: "Print a Grafana dashboard JSON with instance and service variables and one panel " +
+		"for every metric the exporter registers.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logrus.Debug("generateDashboardCmd invoked: generating Grafana dashboard")
+
+		if err := config.SetLogLevel(logLevel); err != nil {
+			logrus.Errorf("Failed to set log level: %v", err)
+			return fmt.Errorf("failed to set log level: %w", err)
+		}
+		return writeDashboard(cmd.OutOrStdout(), grafana.Options{Title: generateDashboardTitle, UID: generateDashboardUID})
+	},
+}
+
+// writeDashboard prints the Grafana dashboard built from the exporter's descriptors.
+func writeDashboard(w io.Writer, opts grafana.Options) error {
+	exp, err := exporter.NewExporter(&config.Config{})
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(grafana.New(exp.Descriptors(), opts), "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to encode dashboard: %w", err)
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// writeRules validates the generated rules against the exporter's metrics and prints them in the given format.
+func writeRules(w io.Writer, thresholds rules.Thresholds, format string, metadata rules.Metadata) error {
+	exp, err := exporter.NewExporter(&config.Config{})
+	if err != nil {
+		return err
+	}
+	file := rules.Generate(thresholds)
+	if err := rules.Validate(file, exp.Descriptors()); err != nil {
+		return fmt.Errorf("generated rules are invalid: %w", err)
+	}
+
+	var doc any
+	switch format {
+	case "rules":
+		doc = file
+	case "prometheusrule":
+		doc = rules.NewPrometheusRule(file, metadata)
+	default:
+		return fmt.Errorf("unsupported format '%s' (expected rules or prometheusrule)", format)
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("unable to encode rules: %w", err)
+	}
+	if data, err = yaml.JSONToYAML(data); err != nil {
+		return fmt.Errorf("unable to encode rules: %w", err)
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+func init() {
+	RootCmd.AddCommand(generateCmd)
+	generateCmd.AddCommand(generateRulesCmd)
+	generateCmd.AddCommand(generateDashboardCmd)
+
+	flags := generateRulesCmd.Flags()
+	flags.StringVarP(&generateRulesFormat, "format", "o", "rules", "Output format (rules, prometheusrule).")
+	flags.StringVar(&generateRulesName, "name", "monit-exporter", "Name of the PrometheusRule resource.")
+	flags.StringVar(&generateRulesNamespace, "namespace", "", "Namespace of the PrometheusRule resource.")
+	flags.StringArrayVar(&generateRulesLabels, "label", nil,
+		"Label added to the PrometheusRule resource as name=value (repeatable).")
+	flags.DurationVar(&generateRulesThresholds.ExporterDownFor, "exporter-down-for", generateRulesThresholds.ExporterDownFor,
+		"How long Monit must be unreachable before MonitExporterDown fires.")
+	flags.DurationVar(&generateRulesThresholds.ServiceFailingFor, "service-failing-for", generateRulesThresholds.ServiceFailingFor,
+		"How long a service failure must persist before MonitServiceFailing fires.")
+	flags.DurationVar(&generateRulesThresholds.For, "for", generateRulesThresholds.For,
+		"Pending period of the filesystem, certificate and staleness alerts.")
+	flags.Float64Var(&generateRulesThresholds.FilesystemPercent, "filesystem-threshold", generateRulesThresholds.FilesystemPercent,
+		"Block usage percentage at which a filesystem is considered nearly full.")
+	flags.Float64Var(&generateRulesThresholds.InodePercent, "inode-threshold", generateRulesThresholds.InodePercent,
+		"Inode usage percentage at which a filesystem is considered nearly exhausted.")
+	flags.Float64Var(&generateRulesThresholds.CertificateDays, "certificate-days", generateRulesThresholds.CertificateDays,
+		"Remaining certificate validity in days that triggers MonitCertificateExpiring.")
+	flags.DurationVar(&generateRulesThresholds.StaleAfter, "stale-after", generateRulesThresholds.StaleAfter,
+		"Age of the last Monit collection after which MonitCollectionStale fires.")
+
+	generateDashboardCmd.Flags().StringVar(&generateDashboardTitle, "title", "Monit", "Title of the dashboard.")
+	generateDashboardCmd.Flags().StringVar(&generateDashboardUID, "uid", "monit-exporter",
+		"UID of the dashboard; keep it stable so re-imports replace the existing dashboard.")
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/ririnto/monit-exporter/internal/grafana"
+	"github.com/ririnto/monit-exporter/internal/rules"
+	"sigs.k8s.io/yaml"
+)
+
+func TestWriteRules(t *testing.T) {
+	t.Run("rules", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		if err := writeRules(buf, rules.DefaultThresholds(), "rules", rules.Metadata{}); err != nil {
+			t.Fatalf("writeRules failed: %v", err)
+		}
+		var file rules.File
+		if err := yaml.Unmarshal(buf.Bytes(), &file); err != nil {
+			t.Fatalf("Invalid YAML output: %v", err)
+		}
+		if len(file.Groups) != 2 {
+			t.Errorf("Expected 2 rule groups, got %d", len(file.Groups))
+		}
+	})
+
+	t.Run("prometheusrule", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		metadata := rules.Metadata{Name: "monit", Labels: map[string]string{"release": "prometheus"}}
+		if err := writeRules(buf, rules.DefaultThresholds(), "prometheusrule", metadata); err != nil {
+			t.Fatalf("writeRules failed: %v", err)
+		}
+		var resource rules.PrometheusRule
+		if err := yaml.Unmarshal(buf.Bytes(), &resource); err != nil {
+			t.Fatalf("Invalid YAML output: %v", err)
+		}
+		if resource.Kind != "PrometheusRule" || resource.Metadata.Labels["release"] != "prometheus" {
+			t.Errorf("Expected a labelled PrometheusRule, got %+v", resource.Metadata)
+		}
+		if len(resource.Spec.Groups) != 2 {
+			t.Errorf("Expected 2 rule groups, got %d", len(resource.Spec.Groups))
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		if err := writeRules(new(bytes.Buffer), rules.DefaultThresholds(), "json", rules.Metadata{}); err == nil {
+			t.Error("Expected an error for an unsupported format, got nil")
+		}
+	})
+}
+
+func TestWriteDashboard(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := writeDashboard(buf, grafana.Options{Title: "Hosts", UID: "hosts"}); err != nil {
+		t.Fatalf("writeDashboard failed: %v", err)
+	}
+	var dashboard grafana.Dashboard
+	if err := json.Unmarshal(buf.Bytes(), &dashboard); err != nil {
+		t.Fatalf("Invalid JSON output: %v", err)
+	}
+	if dashboard.Title != "Hosts" || dashboard.UID != "hosts" {
+		t.Errorf("Expected title Hosts and uid hosts, got %q/%q", dashboard.Title, dashboard.UID)
+	}
+	if !slices.Contains(dashboard.Metrics(), "monit_service_block_usage_percent") {
+		t.Errorf("Expected a block usage panel, got %v", dashboard.Metrics())
+	}
+}
+
+func TestGenerateRulesCmd_LabelWithComma(t *testing.T) {
+	defer func() { generateRulesLabels = nil }()
+	if err := generateRulesCmd.Flags().Set("label", "teams=infra,sre"); err != nil {
+		t.Fatalf("Failed to set --label: %v", err)
+	}
+	if len(generateRulesLabels) != 1 || generateRulesLabels[0] != "teams=infra,sre" {
+		t.Errorf("Expected a single label value, got %v", generateRulesLabels)
+	}
+}
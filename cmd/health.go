@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/ririnto/monit-exporter/internal/backoff"
+	"github.com/ririnto/monit-exporter/internal/exporter"
+	"github.com/sirupsen/logrus"
+)
+
+// fetchStateProvider exposes the outcome of the most recent Monit fetches.
+type fetchStateProvider interface {
+	State() exporter.FetchState
+}
+
+// healthResponse is the JSON body returned by the health endpoints.
+type healthResponse struct {
+	Status      string     `json:"status"`
+	LastAttempt *time.Time `json:"last_attempt,omitempty"`
+	LastSuccess *time.Time `json:"last_success,omitempty"`
+	LastError   string     `json:"last_error,omitempty"`
+}
+
+// writeJSON encodes v as the JSON response body with the given status code.
+func writeJSON(w http.ResponseWriter, statusCode int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logrus.Warnf("writeJSON: failed to encode response: %v", err)
+	}
+}
+
+// healthyHandler reports that the exporter process is alive.
+func healthyHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, healthResponse{Status: "healthy"})
+	}
+}
+
+// readyHandler reports whether the last successful Monit fetch happened within window.
+// It only inspects the recorded fetch state and never contacts Monit itself.
+func readyHandler(provider fetchStateProvider, window time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		state := provider.State()
+		resp := healthResponse{Status: "ready"}
+		if !state.LastAttempt.IsZero() {
+			resp.LastAttempt = &state.LastAttempt
+		}
+		if !state.LastSuccess.IsZero() {
+			resp.LastSuccess = &state.LastSuccess
+		}
+		if state.LastError != nil {
+			resp.LastError = state.LastError.Error()
+		}
+
+		if state.LastSuccess.IsZero() || window < time.Since(state.LastSuccess) {
+			logrus.Debugf("readyHandler: not ready, last successful fetch at %v", state.LastSuccess)
+			resp.Status = "not ready"
+			writeJSON(w, http.StatusServiceUnavailable, resp)
+			return
+		}
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
+
+// primeFetchState fetches Monit in the background, retrying with b, until a fetch has
+// succeeded, so /-/ready can report ready before anything scrapes the exporter.
+// The returned function stops the fetches.
+func primeFetchState(exp *exporter.Exporter, b backoff.Backoff) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for attempt := 0; exp.State().LastSuccess.IsZero(); attempt++ {
+			if _, err := exp.FetchContext(ctx); err == nil {
+				logrus.Debug("primeFetchState: initial Monit fetch succeeded")
+				return
+			}
+			if err := backoff.Sleep(ctx, b.Duration(attempt)); err != nil {
+				return
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ririnto/monit-exporter/internal/backoff"
+	"github.com/ririnto/monit-exporter/internal/config"
+	"github.com/ririnto/monit-exporter/internal/exporter"
+)
+
+type fakeStateProvider struct {
+	state exporter.FetchState
+}
+
+func (f fakeStateProvider) State() exporter.FetchState {
+	return f.state
+}
+
+func TestHealthyHandler(t *testing.T) {
+	w := httptest.NewRecorder()
+	healthyHandler()(w, httptest.NewRequest("GET", "/-/healthy", nil))
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+	var resp healthResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.Status != "healthy" {
+		t.Errorf("Expected status 'healthy', got '%s'", resp.Status)
+	}
+}
+
+func TestReadyHandler(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name      string
+		state     exporter.FetchState
+		wantCode  int
+		wantError string
+	}{
+		{
+			name:     "never fetched",
+			state:    exporter.FetchState{},
+			wantCode: http.StatusServiceUnavailable,
+		},
+		{
+			name:     "recent success",
+			state:    exporter.FetchState{LastAttempt: now, LastSuccess: now},
+			wantCode: http.StatusOK,
+		},
+		{
+			name: "stale success with error",
+			state: exporter.FetchState{
+				LastAttempt: now,
+				LastSuccess: now.Add(-10 * time.Minute),
+				LastError:   errors.New("connection refused"),
+			},
+			wantCode:  http.StatusServiceUnavailable,
+			wantError: "connection refused",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			readyHandler(fakeStateProvider{state: tt.state}, 5*time.Minute)(w, httptest.NewRequest("GET", "/-/ready", nil))
+
+			if w.Code != tt.wantCode {
+				t.Errorf("Expected status %d, got %d", tt.wantCode, w.Code)
+			}
+			var resp healthResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if resp.LastError != tt.wantError {
+				t.Errorf("Expected last_error '%s', got '%s'", tt.wantError, resp.LastError)
+			}
+		})
+	}
+}
+
+func TestPrimeFetchState(t *testing.T) {
+	var requests atomic.Int32
+	monitServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(dumpTestXML))
+	}))
+	defer monitServer.Close()
+	exp, err := exporter.NewExporter(&config.Config{MonitScrapeURI: monitServer.URL, ScrapeTimeout: time.Second})
+	if err != nil {
+		t.Fatalf("NewExporter returned error: %v", err)
+	}
+
+	// Without any scrape the exporter becomes ready once Monit answers.
+	stop := primeFetchState(exp, backoff.Backoff{Min: 10 * time.Millisecond})
+	defer stop()
+	ready := readyHandler(exp, time.Minute)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		w := httptest.NewRecorder()
+		ready(w, httptest.NewRequest("GET", "/-/ready", nil))
+		if w.Code == http.StatusOK {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the exporter to become ready, got status %d", w.Code)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	stop()
+	if requests.Load() != 3 {
+		t.Errorf("Expected fetches to stop after the first success, got %d requests", requests.Load())
+	}
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/ririnto/monit-exporter/internal/exporter"
+	"github.com/sirupsen/logrus"
+)
+
+// scrapeTimeoutHeader is the header in which Prometheus announces the scrape timeout.
+const scrapeTimeoutHeader = "X-Prometheus-Scrape-Timeout-Seconds"
+
+var scrapeTimeoutOffset time.Duration
+
+// requestScrapeTimeout returns the time left for collecting a scrape announcing its timeout in r,
+// reduced by offset so the response still reaches Prometheus in time.
+func requestScrapeTimeout(r *http.Request, offset time.Duration) (time.Duration, bool) {
+	value := r.Header.Get(scrapeTimeoutHeader)
+	if value == "" {
+		return 0, false
+	}
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil || seconds <= 0 {
+		logrus.Warnf("requestScrapeTimeout: ignoring invalid %s header '%s'", scrapeTimeoutHeader, value)
+		return 0, false
+	}
+	timeout := time.Duration(seconds * float64(time.Second))
+	if offset < timeout {
+		timeout -= offset
+	}
+	return timeout, true
+}
+
+// metricsHandler serves the exporter metrics together with those of the default registry.
+// The Monit fetch of each scrape, including retries, is bounded by the scrape timeout
+// Prometheus announces, so a slow Monit is reported as down before Prometheus gives up.
+// Without the header the --monit-timeout deadline applies.
+func metricsHandler(exp *exporter.Exporter, offset time.Duration) http.Handler {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if timeout, ok := requestScrapeTimeout(r, offset); ok {
+			logrus.Debugf("metricsHandler: collecting within the scrape timeout of %s", timeout)
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(exp.WithContext(ctx))
+		gatherers := prometheus.Gatherers{prometheus.DefaultGatherer, registry}
+		promhttp.HandlerFor(gatherers, promhttp.HandlerOpts{}).ServeHTTP(w, r)
+	})
+	return promhttp.InstrumentMetricHandler(prometheus.DefaultRegisterer, handler)
+}
+
+func init() {
+	serveCmd.Flags().DurationVar(&scrapeTimeoutOffset, "scrape-timeout-offset", 500*time.Millisecond,
+		"Time subtracted from the Prometheus scrape timeout to leave room for sending the response.")
+}
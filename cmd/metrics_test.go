@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ririnto/monit-exporter/internal/config"
+	"github.com/ririnto/monit-exporter/internal/exporter"
+)
+
+func TestRequestScrapeTimeout(t *testing.T) {
+	tests := map[string]time.Duration{
+		"10":   9500 * time.Millisecond,
+		"2.5":  2 * time.Second,
+		"0.25": 250 * time.Millisecond,
+	}
+	for header, want := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		req.Header.Set(scrapeTimeoutHeader, header)
+		if got, ok := requestScrapeTimeout(req, 500*time.Millisecond); !ok || got != want {
+			t.Errorf("Expected %s for header %s, got %s (ok=%t)", want, header, got, ok)
+		}
+	}
+	for _, header := range []string{"", "soon", "-1"} {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		req.Header.Set(scrapeTimeoutHeader, header)
+		if _, ok := requestScrapeTimeout(req, 0); ok {
+			t.Errorf("Expected header %q to be ignored", header)
+		}
+	}
+}
+
+func TestMetricsHandler_ScrapeTimeout(t *testing.T) {
+	hung := make(chan struct{})
+	monitServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-hung:
+		}
+	}))
+	defer monitServer.Close()
+	defer close(hung)
+
+	exp, err := exporter.NewExporter(&config.Config{
+		MonitScrapeURI:  monitServer.URL,
+		ScrapeTimeout:   time.Minute,
+		MaxRetries:      5,
+		RetryMinBackoff: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create exporter: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set(scrapeTimeoutHeader, "0.5")
+	rec := httptest.NewRecorder()
+	start := time.Now()
+	metricsHandler(exp, 200*time.Millisecond).ServeHTTP(rec, req)
+
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected the scrape to finish within the scrape timeout, took %s", elapsed)
+	}
+	if !strings.Contains(rec.Body.String(), "monit_exporter_up 0") {
+		t.Errorf("Expected monit_exporter_up 0, got:\n%s", rec.Body.String())
+	}
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ririnto/monit-exporter/internal/backoff"
+	"github.com/ririnto/monit-exporter/internal/config"
+	"github.com/ririnto/monit-exporter/internal/exporter"
+	"github.com/ririnto/monit-exporter/internal/notifier"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	notifyURLs         []string
+	notifyFormat       string
+	notifyTemplateFile string
+	notifyHeaders      []string
+	notifyInterval     time.Duration
+	notifyTimeout      time.Duration
+	notifyMaxRetries   int
+	notifyDedupWindow  time.Duration
+	notifyTLS          config.TLSOptions
+)
+
+// newNotifier builds a webhook notifier from the notify flags.
+func newNotifier() (*notifier.Notifier, error) {
+	if err := notifier.ValidateFormat(notifyFormat); err != nil {
+		return nil, err
+	}
+	if notifyInterval <= 0 {
+		return nil, errors.New("--notify-interval must be positive")
+	}
+	pairs, err := parsePairs("--notify-header", notifyHeaders)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig, err := notifyTLS.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("invalid notification TLS configuration: %w", err)
+	}
+
+	n := &notifier.Notifier{
+		URLs:   notifyURLs,
+		Format: notifyFormat,
+		HTTPClient: &http.Client{
+			Timeout:   notifyTimeout,
+			Transport: &http.Transport{Proxy: http.ProxyFromEnvironment, TLSClientConfig: tlsConfig},
+		},
+		Headers:     make(map[string]string, len(pairs)),
+		Backoff:     backoff.Backoff{Min: time.Second, Max: notifyInterval, Jitter: true},
+		MaxRetries:  notifyMaxRetries,
+		DedupWindow: notifyDedupWindow,
+	}
+	for _, pair := range pairs {
+		n.Headers[pair.Name] = pair.Value
+	}
+	if notifyTemplateFile != "" {
+		if notifyFormat != notifier.FormatJSON {
+			return nil, errors.New("--notify-template-file requires --notify-format=json")
+		}
+		if n.Template, err = notifier.LoadTemplate(notifyTemplateFile); err != nil {
+			return nil, err
+		}
+	}
+	return n, nil
+}
+
+// startNotifier polls Monit every --notify-interval and posts state changes to the
+// configured webhooks. The returned function stops polling; it is a no-op when no
+// webhook is configured.
+func startNotifier(exp *exporter.Exporter) (func(), error) {
+	if len(notifyURLs) == 0 {
+		return func() {}, nil
+	}
+	n, err := newNotifier()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		runPeriodically(ctx, notifyInterval, func(ctx context.Context) {
+			status, err := exp.Fetch()
+			if err != nil {
+				logrus.Warnf("Notifier skipped: failed to fetch Monit status: %v", err)
+				return
+			}
+			if err := n.Observe(ctx, status); err != nil {
+				logrus.Errorf("Failed to send notifications: %v", err)
+			}
+		})
+	}()
+	logrus.Infof("Sending %s notifications to %d webhooks, polling every %s", notifyFormat, len(notifyURLs), notifyInterval)
+
+	return func() {
+		cancel()
+		<-done
+	}, nil
+}
+
+func init() {
+	serveCmd.Flags().StringSliceVar(&notifyURLs, "notify-url", nil,
+		"Webhook URL receiving service state change notifications (repeatable; enables the notifier).")
+	serveCmd.Flags().StringVar(&notifyFormat, "notify-format", notifier.FormatJSON,
+		"Notification payload format: json or alertmanager (POST to /api/v2/alerts).")
+	serveCmd.Flags().StringVar(&notifyTemplateFile, "notify-template-file", "",
+		"Go template file rendering the json-format payload from .Host and .Events.")
+	serveCmd.Flags().StringArrayVar(&notifyHeaders, "notify-header", nil, "Header sent with every notification as name=value (repeatable).")
+	serveCmd.Flags().DurationVar(&notifyInterval, "notify-interval", 30*time.Second, "How often to poll Monit for state changes.")
+	serveCmd.Flags().DurationVar(&notifyTimeout, "notify-timeout", 10*time.Second, "Timeout for each notification request.")
+	serveCmd.Flags().IntVar(&notifyMaxRetries, "notify-max-retries", 3, "Retries for notifications failing with network errors, 5xx or 429.")
+	serveCmd.Flags().DurationVar(&notifyDedupWindow, "notify-dedup-window", 5*time.Minute,
+		"Suppress a repeated event of the same kind for a service within this window.")
+	addTLSFlags(serveCmd.Flags(), "notify", "notification webhooks", &notifyTLS)
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ririnto/monit-exporter/internal/config"
+	"github.com/ririnto/monit-exporter/internal/exporter"
+)
+
+func TestStartNotifier(t *testing.T) {
+	statusPath := filepath.Join(t.TempDir(), "status.xml")
+	if err := os.WriteFile(statusPath, []byte(dumpTestXML), 0o600); err != nil {
+		t.Fatalf("Failed to write fixture: %v", err)
+	}
+	exp, err := exporter.NewExporter(&config.Config{MonitScrapeURI: "file://" + statusPath})
+	if err != nil {
+		t.Fatalf("NewExporter returned error: %v", err)
+	}
+
+	bodies := make(chan string, 4)
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies <- r.Header.Get("X-Source") + " " + string(body)
+	}))
+	defer hook.Close()
+
+	defer func() { notifyURLs, notifyHeaders = nil, nil }()
+	notifyURLs = []string{hook.URL}
+	notifyHeaders = []string{"X-Source=monit"}
+
+	stop, err := startNotifier(exp)
+	if err != nil {
+		t.Fatalf("startNotifier returned error: %v", err)
+	}
+	defer stop()
+
+	select {
+	case body := <-bodies:
+		if !strings.HasPrefix(body, "monit ") || !strings.Contains(body, `"service":"nginx"`) {
+			t.Errorf("Unexpected notification %s", body)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected a notification for the failing service")
+	}
+}
+
+func TestNewNotifier_TemplateRequiresJSON(t *testing.T) {
+	defer func() { notifyFormat, notifyTemplateFile = "json", "" }()
+	notifyFormat = "alertmanager"
+	notifyTemplateFile = "payload.tmpl"
+
+	if _, err := newNotifier(); err == nil {
+		t.Error("Expected an error for a template with the alertmanager format, got nil")
+	}
+}
+
+func TestNotifyFlags_HeaderWithComma(t *testing.T) {
+	defer func() { notifyHeaders = nil }()
+	if err := serveCmd.Flags().Set("notify-header", "Accept=text/plain,application/json"); err != nil {
+		t.Fatalf("Failed to set --notify-header: %v", err)
+	}
+	if len(notifyHeaders) != 1 || notifyHeaders[0] != "Accept=text/plain,application/json" {
+		t.Errorf("Expected a single header value, got %v", notifyHeaders)
+	}
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ririnto/monit-exporter/internal/config"
+	"github.com/ririnto/monit-exporter/internal/exporter"
+	"github.com/ririnto/monit-exporter/internal/otlp"
+	"github.com/sirupsen/logrus"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+var (
+	otlpEndpoint string
+	otlpProtocol string
+	otlpInsecure bool
+	otlpHeaders  []string
+	otlpInterval time.Duration
+	otlpTimeout  time.Duration
+	otlpTLS      config.TLSOptions
+)
+
+// startOTLP starts exporting the exporter's metrics over OTLP when --otlp-endpoint is set.
+// The meter provider is built in the background once Monit has reported the resource
+// attributes, so an unreachable Monit never delays or fails the Prometheus endpoint.
+// The returned function flushes and stops the export; it is a no-op when OTLP is disabled.
+func startOTLP(ctx context.Context, exp *exporter.Exporter) (func(), error) {
+	if otlpEndpoint == "" {
+		return func() {}, nil
+	}
+
+	pairs, err := parsePairs("--otlp-header", otlpHeaders)
+	if err != nil {
+		return nil, err
+	}
+	headers := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		headers[pair.Name] = pair.Value
+	}
+	tlsConfig, err := otlpTLS.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("invalid OTLP TLS configuration: %w", err)
+	}
+
+	metricExporter, err := otlp.NewMetricExporter(ctx, otlp.Options{
+		Endpoint:  otlpEndpoint,
+		Protocol:  otlpProtocol,
+		Insecure:  otlpInsecure,
+		Headers:   headers,
+		TLSConfig: tlsConfig,
+		Timeout:   otlpTimeout,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	providerCh := make(chan *sdkmetric.MeterProvider, 1)
+	go func() {
+		defer close(providerCh)
+		provider, err := otlp.NewMeterProvider(ctx, exp,
+			sdkmetric.NewPeriodicReader(metricExporter, sdkmetric.WithInterval(otlpInterval)))
+		if err != nil {
+			if ctx.Err() == nil {
+				logrus.Errorf("Failed to create OTLP meter provider: %v", err)
+			}
+			return
+		}
+		logrus.Infof("Exporting metrics over OTLP/%s to %s every %s", otlpProtocol, otlpEndpoint, otlpInterval)
+		providerCh <- provider
+	}()
+
+	return func() {
+		cancel()
+		provider, ok := <-providerCh
+		if !ok {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), otlpTimeout)
+		defer cancel()
+		if err := provider.Shutdown(ctx); err != nil {
+			logrus.Errorf("Failed to shut down OTLP export: %v", err)
+		}
+	}, nil
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&otlpEndpoint, "otlp-endpoint", "",
+		"OTLP collector endpoint as host:port or URL; enables OTLP export alongside the Prometheus endpoint.")
+	serveCmd.Flags().StringVar(&otlpProtocol, "otlp-protocol", otlp.ProtocolGRPC, "OTLP transport protocol (grpc or http).")
+	serveCmd.Flags().BoolVar(&otlpInsecure, "otlp-insecure", false, "Use plaintext instead of TLS for the OTLP connection.")
+	serveCmd.Flags().StringArrayVar(&otlpHeaders, "otlp-header", nil, "Header sent with every OTLP export as name=value (repeatable).")
+	serveCmd.Flags().DurationVar(&otlpInterval, "otlp-interval", time.Minute, "How often to collect Monit status and export over OTLP.")
+	serveCmd.Flags().DurationVar(&otlpTimeout, "otlp-timeout", 10*time.Second, "Timeout for each OTLP export.")
+	addTLSFlags(serveCmd.Flags(), "otlp", "the OTLP collector", &otlpTLS)
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ririnto/monit-exporter/internal/config"
+	"github.com/ririnto/monit-exporter/internal/exporter"
+)
+
+func TestStartOTLP_Disabled(t *testing.T) {
+	stop, err := startOTLP(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("startOTLP returned error: %v", err)
+	}
+	stop()
+}
+
+func TestStartOTLP_HTTP(t *testing.T) {
+	statusPath := filepath.Join(t.TempDir(), "status.xml")
+	if err := os.WriteFile(statusPath, []byte(dumpTestXML), 0o600); err != nil {
+		t.Fatalf("Failed to write fixture: %v", err)
+	}
+	exp, err := exporter.NewExporter(&config.Config{MonitScrapeURI: "file://" + statusPath})
+	if err != nil {
+		t.Fatalf("NewExporter returned error: %v", err)
+	}
+
+	var requests atomic.Int32
+	var tenant atomic.Value
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		tenant.Store(r.Header.Get("X-Tenant"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer collector.Close()
+
+	defer func() {
+		otlpEndpoint, otlpProtocol, otlpHeaders, otlpInterval, otlpTimeout = "", "grpc", nil, time.Minute, 10*time.Second
+	}()
+	otlpEndpoint = collector.URL
+	otlpProtocol = "http"
+	otlpHeaders = []string{"X-Tenant=edge"}
+	otlpInterval = 50 * time.Millisecond
+	otlpTimeout = 5 * time.Second
+
+	stop, err := startOTLP(context.Background(), exp)
+	if err != nil {
+		t.Fatalf("startOTLP returned error: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for requests.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	stop()
+
+	if requests.Load() == 0 {
+		t.Fatal("Expected an OTLP export")
+	}
+	if tenant.Load() != "edge" {
+		t.Errorf("Expected X-Tenant 'edge', got '%v'", tenant.Load())
+	}
+}
+
+func TestStartOTLP_MonitDown(t *testing.T) {
+	monitServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer monitServer.Close()
+	exp, err := exporter.NewExporter(&config.Config{MonitScrapeURI: monitServer.URL, ScrapeTimeout: time.Second})
+	if err != nil {
+		t.Fatalf("NewExporter returned error: %v", err)
+	}
+
+	defer func() { otlpEndpoint, otlpProtocol = "", "grpc" }()
+	otlpEndpoint = "http://127.0.0.1:1/v1/metrics"
+	otlpProtocol = "http"
+
+	start := time.Now()
+	stop, err := startOTLP(context.Background(), exp)
+	if err != nil {
+		t.Fatalf("Expected startOTLP to succeed while Monit is down, got: %v", err)
+	}
+	stop()
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected startOTLP not to wait for Monit, took %s", elapsed)
+	}
+}
+
+func TestStartOTLP_InvalidProtocol(t *testing.T) {
+	defer func() { otlpEndpoint, otlpProtocol = "", "grpc" }()
+	otlpEndpoint = "localhost:4317"
+	otlpProtocol = "udp"
+
+	if _, err := startOTLP(context.Background(), nil); err == nil {
+		t.Error("Expected an error for an invalid protocol, got nil")
+	}
+}
+
+func TestOTLPFlags_HeaderWithComma(t *testing.T) {
+	defer func() { otlpHeaders = nil }()
+	if err := serveCmd.Flags().Set("otlp-header", "Accept=text/plain,application/json"); err != nil {
+		t.Fatalf("Failed to set --otlp-header: %v", err)
+	}
+	if len(otlpHeaders) != 1 || otlpHeaders[0] != "Accept=text/plain,application/json" {
+		t.Errorf("Expected a single header value, got %v", otlpHeaders)
+	}
+}
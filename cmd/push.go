@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/push"
+	"github.com/ririnto/monit-exporter/internal/config"
+	"github.com/ririnto/monit-exporter/internal/exporter"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pushURL      string
+	pushJob      string
+	pushInstance string
+	pushGrouping []string
+	pushUser     string
+	pushPassword string
+	pushReplace  bool
+	pushInterval time.Duration
+	pushTimeout  time.Duration
+	pushTLS      config.TLSOptions
+)
+
+var pushCmd = &cobra.Command{
+	Use:   "push",
+	Short: "Push metrics to a Prometheus Pushgateway",
+	Long: "Collect the Monit status once (or every --interval) and push the exporter's metrics " +
+		"to a Prometheus Pushgateway under the job/instance grouping key.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logrus.Debug("pushCmd invoked: pushing metrics to Pushgateway")
+
+		if err := config.SetLogLevel(logLevel); err != nil {
+			logrus.Errorf("Failed to set log level: %v", err)
+			return fmt.Errorf("failed to set log level: %w", err)
+		}
+		if pushURL == "" {
+			return errors.New("--pushgateway-url is required")
+		}
+
+		cfg, err := newConfig()
+		if err != nil {
+			return err
+		}
+		exp, err := exporter.NewExporter(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to create exporter: %w", err)
+		}
+		pusher, err := newPusher(exp)
+		if err != nil {
+			return err
+		}
+
+		if pushInterval <= 0 {
+			return pushOnce(context.Background(), pusher)
+		}
+
+		ctx, cancel := signalContext()
+		defer cancel()
+		logrus.Infof("Pushing metrics to %s every %s", pushURL, pushInterval)
+		runPeriodically(ctx, pushInterval, func(ctx context.Context) {
+			if err := pushOnce(ctx, pusher); err != nil {
+				logrus.Errorf("Failed to push metrics: %v", err)
+			}
+		})
+		logrus.Info("Pushgateway pusher stopped")
+		return nil
+	},
+}
+
+// newPusher builds a Pushgateway pusher for the exporter from the push flags.
+func newPusher(exp *exporter.Exporter) (*push.Pusher, error) {
+	tlsConfig, err := pushTLS.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("invalid Pushgateway TLS configuration: %w", err)
+	}
+	client := &http.Client{
+		Timeout:   pushTimeout,
+		Transport: &http.Transport{Proxy: http.ProxyFromEnvironment, TLSClientConfig: tlsConfig},
+	}
+
+	pusher := push.New(pushURL, pushJob).Collector(exp).Client(client)
+	instance := pushInstance
+	if instance == "" {
+		if instance, err = os.Hostname(); err != nil {
+			return nil, fmt.Errorf("unable to determine hostname for instance grouping: %w", err)
+		}
+	}
+	pusher = pusher.Grouping("instance", instance)
+	for _, grouping := range pushGrouping {
+		name, value, ok := strings.Cut(grouping, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid --grouping '%s' (expected name=value)", grouping)
+		}
+		pusher = pusher.Grouping(name, value)
+	}
+	if pushUser != "" {
+		pusher = pusher.BasicAuth(pushUser, pushPassword)
+	}
+	return pusher, nil
+}
+
+// pushOnce collects and pushes the metrics, replacing (PUT) or adding to (POST) the group.
+func pushOnce(ctx context.Context, pusher *push.Pusher) error {
+	if pushReplace {
+		return pusher.PushContext(ctx)
+	}
+	return pusher.AddContext(ctx)
+}
+
+func init() {
+	RootCmd.AddCommand(pushCmd)
+
+	pushCmd.Flags().StringVar(&pushURL, "pushgateway-url", "", "Pushgateway base URL (required).")
+	pushCmd.Flags().StringVar(&pushJob, "job", "monit", "Job name used as grouping key.")
+	pushCmd.Flags().StringVar(&pushInstance, "instance", "", "Instance grouping key (defaults to the hostname).")
+	pushCmd.Flags().StringArrayVar(&pushGrouping, "grouping", nil, "Additional grouping key as name=value (repeatable).")
+	pushCmd.Flags().StringVar(&pushUser, "pushgateway-user", "", "Basic auth username for the Pushgateway.")
+	pushCmd.Flags().StringVar(&pushPassword, "pushgateway-password", "", "Basic auth password for the Pushgateway.")
+	pushCmd.Flags().BoolVar(&pushReplace, "replace", true,
+		"Replace all metrics of the group (PUT); when false, only same-named metrics are replaced (POST).")
+	pushCmd.Flags().DurationVar(&pushInterval, "interval", 0, "Push every interval until stopped (0 pushes once).")
+	pushCmd.Flags().DurationVar(&pushTimeout, "pushgateway-timeout", 10*time.Second, "Timeout for each push request.")
+	addTLSFlags(pushCmd.Flags(), "pushgateway", "the Pushgateway", &pushTLS)
+}
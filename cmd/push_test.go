@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPushCmd_Once(t *testing.T) {
+	statusPath := filepath.Join(t.TempDir(), "status.xml")
+	if err := os.WriteFile(statusPath, []byte(dumpTestXML), 0o600); err != nil {
+		t.Fatalf("Failed to write fixture: %v", err)
+	}
+
+	var gotMethod, gotPath, gotUser string
+	var gotBody []byte
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotPath = r.Method, r.URL.Path
+		gotUser, _, _ = r.BasicAuth()
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer gateway.Close()
+
+	previousURI := monitScrapeURI
+	defer func() {
+		monitScrapeURI = previousURI
+		pushURL, pushInstance, pushGrouping, pushUser, pushPassword = "", "", nil, "", ""
+	}()
+	monitScrapeURI = "file://" + statusPath
+	pushURL = gateway.URL
+	pushInstance = "web-1"
+	pushGrouping = []string{"site=edge"}
+	pushUser, pushPassword = "pusher", "secret"
+
+	if err := pushCmd.RunE(pushCmd, nil); err != nil {
+		t.Fatalf("pushCmd returned error: %v", err)
+	}
+	if gotMethod != http.MethodPut {
+		t.Errorf("Expected PUT, got %s", gotMethod)
+	}
+	// The Pushgateway client does not order the grouping labels.
+	if gotPath != "/metrics/job/monit/instance/web-1/site/edge" && gotPath != "/metrics/job/monit/site/edge/instance/web-1" {
+		t.Errorf("Unexpected grouping path %s", gotPath)
+	}
+	if gotUser != "pusher" {
+		t.Errorf("Expected basic auth user 'pusher', got '%s'", gotUser)
+	}
+	if !strings.Contains(string(gotBody), "monit_exporter_up") {
+		t.Error("Expected pushed body to contain monit_exporter_up")
+	}
+}
+
+func TestPushCmd_InvalidGrouping(t *testing.T) {
+	defer func() { pushURL, pushGrouping = "", nil }()
+	pushURL = "http://localhost:9091"
+	pushGrouping = []string{"novalue"}
+
+	if err := pushCmd.RunE(pushCmd, nil); err == nil {
+		t.Error("Expected an error for an invalid grouping, got nil")
+	}
+}
+
+func TestPushCmd_GroupingWithComma(t *testing.T) {
+	defer func() { pushGrouping = nil }()
+	if err := pushCmd.Flags().Set("grouping", "zones=a,b"); err != nil {
+		t.Fatalf("Failed to set --grouping: %v", err)
+	}
+	if len(pushGrouping) != 1 || pushGrouping[0] != "zones=a,b" {
+		t.Errorf("Expected a single grouping value, got %v", pushGrouping)
+	}
+}
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/ririnto/monit-exporter/internal/backoff"
+	"github.com/ririnto/monit-exporter/internal/config"
+	"github.com/ririnto/monit-exporter/internal/exporter"
+	"github.com/ririnto/monit-exporter/internal/remotewrite"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	remoteWriteURL             string
+	remoteWriteInterval        time.Duration
+	remoteWriteWALDirectory    string
+	remoteWriteWALMaxSegments  int
+	remoteWriteExternalLabels  []string
+	remoteWriteHeaders         []string
+	remoteWriteUser            string
+	remoteWritePassword        string
+	remoteWriteBearerTokenFile string
+	remoteWriteTimeout         time.Duration
+	remoteWriteMaxRetries      int
+	remoteWriteOnce            bool
+	remoteWriteTLS             config.TLSOptions
+)
+
+var remoteWriteCmd = &cobra.Command{
+	Use:   "remote-write",
+	Short: "Send metrics to a Prometheus remote-write endpoint",
+	Long: "Run as an agent: poll Monit every --interval and send the exporter's metrics to a Prometheus " +
+		"remote-write endpoint. Requests are buffered in an on-disk WAL while the endpoint is unreachable " +
+		"and delivered in order once it recovers.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logrus.Debug("remoteWriteCmd invoked: starting remote-write agent")
+
+		if err := config.SetLogLevel(logLevel); err != nil {
+			logrus.Errorf("Failed to set log level: %v", err)
+			return fmt.Errorf("failed to set log level: %w", err)
+		}
+		if remoteWriteURL == "" {
+			return errors.New("--remote-write-url is required")
+		}
+		if remoteWriteInterval <= 0 {
+			return errors.New("--interval must be positive")
+		}
+
+		cfg, err := newConfig()
+		if err != nil {
+			return err
+		}
+		exp, err := exporter.NewExporter(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to create exporter: %w", err)
+		}
+		sender, err := newRemoteWriteSender(exp)
+		if err != nil {
+			return err
+		}
+
+		if remoteWriteOnce {
+			return sender.Tick(context.Background())
+		}
+
+		ctx, cancel := signalContext()
+		defer cancel()
+		logrus.Infof("Sending metrics to %s every %s", remoteWriteURL, remoteWriteInterval)
+		runPeriodically(ctx, remoteWriteInterval, func(ctx context.Context) {
+			if err := sender.Tick(ctx); err != nil {
+				logrus.Errorf("Failed to send metrics: %v", err)
+			}
+		})
+		logrus.Info("Remote-write agent stopped")
+		return nil
+	},
+}
+
+// newRemoteWriteSender builds a remote-write sender for the exporter from the remote-write flags.
+func newRemoteWriteSender(exp *exporter.Exporter) (*remotewrite.Sender, error) {
+	externalLabels, err := parsePairs("--external-label", remoteWriteExternalLabels)
+	if err != nil {
+		return nil, err
+	}
+	headers, err := parsePairs("--remote-write-header", remoteWriteHeaders)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig, err := remoteWriteTLS.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote-write TLS configuration: %w", err)
+	}
+	client := &remotewrite.Client{
+		URL: remoteWriteURL,
+		HTTPClient: &http.Client{
+			Timeout:   remoteWriteTimeout,
+			Transport: &http.Transport{Proxy: http.ProxyFromEnvironment, TLSClientConfig: tlsConfig},
+		},
+		Headers:  make(map[string]string, len(headers)),
+		Username: remoteWriteUser,
+		Password: remoteWritePassword,
+	}
+	for _, header := range headers {
+		client.Headers[header.Name] = header.Value
+	}
+	if remoteWriteBearerTokenFile != "" {
+		data, err := os.ReadFile(remoteWriteBearerTokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read remote-write bearer token file: %w", err)
+		}
+		client.BearerToken = strings.TrimSpace(string(data))
+	}
+
+	wal, err := remotewrite.OpenWAL(remoteWriteWALDirectory, remoteWriteWALMaxSegments)
+	if err != nil {
+		return nil, err
+	}
+
+	registry := prometheus.NewRegistry()
+	if err := registry.Register(exp); err != nil {
+		return nil, fmt.Errorf("failed to register exporter: %w", err)
+	}
+	return &remotewrite.Sender{
+		Gatherer:       registry,
+		Client:         client,
+		WAL:            wal,
+		ExternalLabels: externalLabels,
+		Backoff:        backoff.Backoff{Min: time.Second, Max: remoteWriteInterval, Jitter: true},
+		MaxRetries:     remoteWriteMaxRetries,
+	}, nil
+}
+
+// parsePairs parses name=value flag values.
+func parsePairs(flag string, values []string) ([]remotewrite.Label, error) {
+	pairs := make([]remotewrite.Label, 0, len(values))
+	for _, value := range values {
+		name, v, ok := strings.Cut(value, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid %s '%s' (expected name=value)", flag, value)
+		}
+		pairs = append(pairs, remotewrite.Label{Name: name, Value: v})
+	}
+	return pairs, nil
+}
+
+func init() {
+	RootCmd.AddCommand(remoteWriteCmd)
+
+	remoteWriteCmd.Flags().StringVar(&remoteWriteURL, "remote-write-url", "", "Remote-write endpoint URL (required).")
+	remoteWriteCmd.Flags().DurationVar(&remoteWriteInterval, "interval", 30*time.Second,
+		"How often to collect Monit status and send the metrics.")
+	remoteWriteCmd.Flags().StringVar(&remoteWriteWALDirectory, "wal-directory", "data/remote-write",
+		"Directory buffering write requests until they are delivered.")
+	remoteWriteCmd.Flags().IntVar(&remoteWriteWALMaxSegments, "wal-max-segments", 2880,
+		"Maximum number of buffered write requests; the oldest are dropped beyond it (0 for unlimited).")
+	remoteWriteCmd.Flags().StringArrayVar(&remoteWriteExternalLabels, "external-label", nil,
+		"Label added to every series as name=value (repeatable).")
+	remoteWriteCmd.Flags().StringArrayVar(&remoteWriteHeaders, "remote-write-header", nil,
+		"Extra HTTP header sent with every request as name=value (repeatable).")
+	remoteWriteCmd.Flags().StringVar(&remoteWriteUser, "remote-write-user", "", "Basic auth username for the remote-write endpoint.")
+	remoteWriteCmd.Flags().StringVar(&remoteWritePassword, "remote-write-password", "", "Basic auth password for the remote-write endpoint.")
+	remoteWriteCmd.Flags().StringVar(&remoteWriteBearerTokenFile, "remote-write-bearer-token-file", "",
+		"File containing a bearer token for the remote-write endpoint.")
+	remoteWriteCmd.Flags().DurationVar(&remoteWriteTimeout, "remote-write-timeout", 30*time.Second, "Timeout for each write request.")
+	remoteWriteCmd.Flags().IntVar(&remoteWriteMaxRetries, "max-retries", 3,
+		"Retries per write request for recoverable errors before it stays buffered until the next interval.")
+	remoteWriteCmd.Flags().BoolVar(&remoteWriteOnce, "once", false, "Collect and send once, then exit.")
+	addTLSFlags(remoteWriteCmd.Flags(), "remote-write", "the remote-write endpoint", &remoteWriteTLS)
+}
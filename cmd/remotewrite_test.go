@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/ririnto/monit-exporter/internal/remotewrite"
+)
+
+func TestRemoteWriteCmd_Once(t *testing.T) {
+	statusPath := filepath.Join(t.TempDir(), "status.xml")
+	if err := os.WriteFile(statusPath, []byte(dumpTestXML), 0o600); err != nil {
+		t.Fatalf("Failed to write fixture: %v", err)
+	}
+
+	var series []remotewrite.TimeSeries
+	var gotHeader string
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header.Get("X-Scope-OrgID")
+		compressed, _ := io.ReadAll(r.Body)
+		data, err := snappy.Decode(nil, compressed)
+		if err == nil {
+			series, err = remotewrite.UnmarshalWriteRequest(data)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	previousURI := monitScrapeURI
+	defer func() {
+		monitScrapeURI = previousURI
+		remoteWriteURL, remoteWriteWALDirectory, remoteWriteOnce = "", "data/remote-write", false
+		remoteWriteExternalLabels, remoteWriteHeaders = nil, nil
+	}()
+	monitScrapeURI = "file://" + statusPath
+	remoteWriteURL = receiver.URL
+	remoteWriteWALDirectory = t.TempDir()
+	remoteWriteExternalLabels = []string{"cluster=edge"}
+	remoteWriteHeaders = []string{"X-Scope-OrgID=tenant"}
+	remoteWriteOnce = true
+
+	if err := remoteWriteCmd.RunE(remoteWriteCmd, nil); err != nil {
+		t.Fatalf("remoteWriteCmd returned error: %v", err)
+	}
+	if gotHeader != "tenant" {
+		t.Errorf("Expected X-Scope-OrgID 'tenant', got '%s'", gotHeader)
+	}
+	up := slices.IndexFunc(series, func(ts remotewrite.TimeSeries) bool {
+		return slices.Contains(ts.Labels, remotewrite.Label{Name: "__name__", Value: "monit_exporter_up"})
+	})
+	if up < 0 {
+		t.Fatal("Expected monit_exporter_up to be sent")
+	}
+	if !slices.Contains(series[up].Labels, remotewrite.Label{Name: "cluster", Value: "edge"}) {
+		t.Errorf("Expected external label cluster=edge, got %v", series[up].Labels)
+	}
+	if series[up].Samples[0].Value != 1 {
+		t.Errorf("Expected monit_exporter_up 1, got %v", series[up].Samples[0].Value)
+	}
+}
+
+func TestRemoteWriteCmd_InvalidExternalLabel(t *testing.T) {
+	defer func() { remoteWriteURL, remoteWriteExternalLabels = "", nil }()
+	remoteWriteURL = "http://localhost:9090/api/v1/write"
+	remoteWriteExternalLabels = []string{"novalue"}
+
+	if err := remoteWriteCmd.RunE(remoteWriteCmd, nil); err == nil {
+		t.Error("Expected an error for an invalid external label, got nil")
+	}
+}
+
+func TestRemoteWriteCmd_HeaderWithComma(t *testing.T) {
+	defer func() { remoteWriteHeaders = nil }()
+	if err := remoteWriteCmd.Flags().Set("remote-write-header", "Accept=text/plain,application/json"); err != nil {
+		t.Fatalf("Failed to set --remote-write-header: %v", err)
+	}
+	if len(remoteWriteHeaders) != 1 || remoteWriteHeaders[0] != "Accept=text/plain,application/json" {
+		t.Errorf("Expected a single header value, got %v", remoteWriteHeaders)
+	}
+}
+
+func TestRemoteWriteCmd_ExternalLabelWithComma(t *testing.T) {
+	defer func() { remoteWriteExternalLabels = nil }()
+	if err := remoteWriteCmd.Flags().Set("external-label", "zones=a,b"); err != nil {
+		t.Fatalf("Failed to set --external-label: %v", err)
+	}
+	if len(remoteWriteExternalLabels) != 1 || remoteWriteExternalLabels[0] != "zones=a,b" {
+		t.Errorf("Expected a single label value, got %v", remoteWriteExternalLabels)
+	}
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+
+	"github.com/ririnto/monit-exporter/internal/config"
+	"github.com/ririnto/monit-exporter/internal/exporter"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var renderCmd = &cobra.Command{
+	Use:   "render [file]",
+	Short: "Render metrics from Monit status once and print them to stdout",
+	Long: "Collect the Monit status once through the exporter and write the Prometheus text exposition to stdout. " +
+		"The status is read from the given XML file, or from --monit-scrape-uri (which also accepts file:// URIs).",
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logrus.Debug("renderCmd invoked: rendering metrics once")
+
+		if err := config.SetLogLevel(logLevel); err != nil {
+			logrus.Errorf("Failed to set log level: %v", err)
+			return fmt.Errorf("failed to set log level: %w", err)
+		}
+
+		cfg, err := newConfig()
+		if err != nil {
+			return err
+		}
+		if 0 < len(args) {
+			path, err := filepath.Abs(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid file path: %w", err)
+			}
+			cfg.MonitScrapeURI = (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+		}
+
+		exp, err := exporter.NewExporter(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to create exporter: %w", err)
+		}
+		if err := exp.WriteText(cmd.OutOrStdout()); err != nil {
+			return fmt.Errorf("failed to render metrics: %w", err)
+		}
+		if state := exp.State(); state.LastError != nil {
+			return fmt.Errorf("failed to collect Monit status: %w", state.LastError)
+		}
+		return nil
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(renderCmd)
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenderCmd(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "status.xml")
+	if err := os.WriteFile(path, []byte(dumpTestXML), 0o600); err != nil {
+		t.Fatalf("Failed to write fixture: %v", err)
+	}
+
+	buf := new(bytes.Buffer)
+	renderCmd.SetOut(buf)
+	if err := renderCmd.RunE(renderCmd, []string{path}); err != nil {
+		t.Fatalf("renderCmd returned error: %v", err)
+	}
+	output := buf.String()
+	for _, want := range []string{"monit_exporter_up 1", `service_name="nginx"`} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestRenderCmd_MissingFile(t *testing.T) {
+	buf := new(bytes.Buffer)
+	renderCmd.SetOut(buf)
+	err := renderCmd.RunE(renderCmd, []string{filepath.Join(t.TempDir(), "missing.xml")})
+	if err == nil {
+		t.Fatal("Expected an error for a missing file, got nil")
+	}
+	if !strings.Contains(buf.String(), "monit_exporter_up 0") {
+		t.Errorf("Expected exporter_up 0 in output, got:\n%s", buf.String())
+	}
+}
@@ -3,19 +3,37 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"time"
 
+	"github.com/ririnto/monit-exporter/internal/config"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var (
-	listenAddress  string
-	metricsPath    string
-	ignoreSSL      bool
-	monitScrapeURI string
-	monitUser      string
-	monitPassword  string
-	logLevel       string
+	listenAddress    string
+	metricsPath      string
+	ignoreSSL        bool
+	monitScrapeURI   string
+	monitUser        string
+	monitPassword    string
+	logLevel         string
+	webConfigFile    string
+	readyWindow      time.Duration
+	controlTokenFile string
+	maxBodySize      int64
+	scrapeTimeout    time.Duration
+	maxRetries       int
+	retryMinBackoff  time.Duration
+	retryMaxBackoff  time.Duration
+	breakerThreshold int
+	breakerCooldown  time.Duration
+	gracePeriod      time.Duration
+
+	monitProxyURL        string
+	monitHeaders         []string
+	monitBearerTokenFile string
 )
 
 // RootCmd is the base command for this application.
@@ -29,6 +47,51 @@ var RootCmd = &cobra.Command{
 	},
 }
 
+// newConfig builds the exporter configuration from the persistent flags.
+func newConfig() (*config.Config, error) {
+	headers, err := parsePairs("--monit-header", monitHeaders)
+	if err != nil {
+		return nil, err
+	}
+	monitHeaderMap := make(map[string]string, len(headers))
+	for _, header := range headers {
+		monitHeaderMap[header.Name] = header.Value
+	}
+	return &config.Config{
+		ListenAddress:    listenAddress,
+		MetricsPath:      metricsPath,
+		IgnoreSSL:        ignoreSSL,
+		MonitScrapeURI:   monitScrapeURI,
+		MonitUser:        monitUser,
+		MonitPassword:    monitPassword,
+		LogLevel:         logLevel,
+		WebConfigFile:    webConfigFile,
+		ReadyWindow:      readyWindow,
+		ControlTokenFile: controlTokenFile,
+		MaxBodySize:      maxBodySize,
+		ScrapeTimeout:    scrapeTimeout,
+		MaxRetries:       maxRetries,
+		RetryMinBackoff:  retryMinBackoff,
+		RetryMaxBackoff:  retryMaxBackoff,
+		BreakerThreshold: breakerThreshold,
+		BreakerCooldown:  breakerCooldown,
+		GracePeriod:      gracePeriod,
+
+		MonitProxyURL:        monitProxyURL,
+		MonitHeaders:         monitHeaderMap,
+		MonitBearerTokenFile: monitBearerTokenFile,
+	}, nil
+}
+
+// addTLSFlags registers the client TLS flags for an outgoing connection under prefix.
+func addTLSFlags(flags *pflag.FlagSet, prefix, target string, opts *config.TLSOptions) {
+	flags.StringVar(&opts.CAFile, prefix+"-ca-file", "", "CA certificate file used to verify "+target+".")
+	flags.StringVar(&opts.CertFile, prefix+"-cert-file", "", "Client certificate file presented to "+target+".")
+	flags.StringVar(&opts.KeyFile, prefix+"-key-file", "", "Client key file presented to "+target+".")
+	flags.BoolVar(&opts.InsecureSkipVerify, prefix+"-insecure-skip-verify", false,
+		"Skip TLS certificate verification for "+target+".")
+}
+
 // Execute runs the root command of the application.
 func Execute() {
 	logrus.Debug("Execute function called: attempting to run RootCmd.Execute()")
@@ -65,6 +128,54 @@ func init() {
 		"http://localhost:2812/_status?format=xml&level=full",
 		"The Monit status URL to scrape (XML format).",
 	)
+	RootCmd.PersistentFlags().Int64Var(
+		&maxBodySize,
+		"monit-max-body-size",
+		32<<20,
+		"Maximum size in bytes of a Monit status document (0 disables the limit).",
+	)
+	RootCmd.PersistentFlags().DurationVar(
+		&scrapeTimeout,
+		"monit-timeout",
+		8*time.Second,
+		"Deadline for fetching the Monit status, including retries, when no Prometheus scrape timeout applies (0 disables it).",
+	)
+	RootCmd.PersistentFlags().IntVar(
+		&maxRetries,
+		"monit-retries",
+		2,
+		"How many times a failed Monit fetch is retried within the deadline.",
+	)
+	RootCmd.PersistentFlags().DurationVar(
+		&retryMinBackoff,
+		"monit-retry-backoff",
+		200*time.Millisecond,
+		"Initial delay between Monit fetch retries, doubled with jitter after each attempt.",
+	)
+	RootCmd.PersistentFlags().DurationVar(
+		&retryMaxBackoff,
+		"monit-retry-max-backoff",
+		2*time.Second,
+		"Maximum delay between Monit fetch retries.",
+	)
+	RootCmd.PersistentFlags().IntVar(
+		&breakerThreshold,
+		"monit-breaker-threshold",
+		5,
+		"Consecutive failed Monit fetches that open the circuit breaker (0 disables the breaker).",
+	)
+	RootCmd.PersistentFlags().DurationVar(
+		&breakerCooldown,
+		"monit-breaker-cooldown",
+		30*time.Second,
+		"How long the open circuit breaker skips fetches before trying Monit again.",
+	)
+	RootCmd.PersistentFlags().DurationVar(
+		&gracePeriod,
+		"monit-grace-period",
+		0,
+		"How long the last good Monit snapshot keeps being served after failed fetches (0 disables it).",
+	)
 	RootCmd.PersistentFlags().StringVar(
 		&monitUser,
 		"monit-user",
@@ -77,10 +188,46 @@ func init() {
 		"",
 		"Basic auth password for accessing Monit.",
 	)
+	RootCmd.PersistentFlags().StringVar(
+		&monitProxyURL,
+		"monit-proxy-url",
+		"",
+		"Proxy URL for reaching Monit, optionally with user:password (defaults to HTTP_PROXY/HTTPS_PROXY; NO_PROXY applies).",
+	)
+	RootCmd.PersistentFlags().StringArrayVar(
+		&monitHeaders,
+		"monit-header",
+		nil,
+		"Extra HTTP header sent with every Monit request as name=value (repeatable).",
+	)
+	RootCmd.PersistentFlags().StringVar(
+		&monitBearerTokenFile,
+		"monit-bearer-token-file",
+		"",
+		"File holding a bearer token sent to Monit instead of basic auth.",
+	)
 	RootCmd.PersistentFlags().StringVar(
 		&logLevel,
 		"log-level",
 		"info",
 		"Log level for the application (debug, info, warn, error, fatal, panic).",
 	)
+	RootCmd.PersistentFlags().StringVar(
+		&webConfigFile,
+		"web-config-file",
+		"",
+		"Path to a web configuration file (exporter-toolkit format) enabling TLS and/or basic auth.",
+	)
+	RootCmd.PersistentFlags().DurationVar(
+		&readyWindow,
+		"ready-window",
+		5*time.Minute,
+		"How recently the last Monit fetch must have succeeded for /-/ready to report ready.",
+	)
+	RootCmd.PersistentFlags().StringVar(
+		&controlTokenFile,
+		"control-token-file",
+		"",
+		"Path to a file holding the X-Monit-Exporter-Token value for the service control API (disabled when empty).",
+	)
 }
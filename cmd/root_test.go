@@ -37,3 +37,38 @@ func TestRootCmd_Execute(t *testing.T) {
 	RootCmd.SetArgs([]string{"test"})
 	Execute()
 }
+
+// TestNewConfig_MonitHeader verifies that --monit-header values are validated and passed to the config.
+func TestNewConfig_MonitHeader(t *testing.T) {
+	t.Cleanup(func() {
+		monitHeaders = nil
+	})
+
+	monitHeaders = []string{"X-Auth-Token=abc", "Accept=text/xml,application/xml"}
+	cfg, err := newConfig()
+	if err != nil {
+		t.Fatalf("Expected valid headers, got %v", err)
+	}
+	if value := cfg.MonitHeaders["Accept"]; value != "text/xml,application/xml" {
+		t.Errorf("Expected header value text/xml,application/xml, got %q", value)
+	}
+
+	monitHeaders = []string{"invalid"}
+	if _, err := newConfig(); err == nil {
+		t.Error("Expected an error for a header without a value, got nil")
+	}
+}
+
+// TestRootCmd_MonitHeaderFlag verifies that a header value containing commas is kept whole.
+func TestRootCmd_MonitHeaderFlag(t *testing.T) {
+	t.Cleanup(func() {
+		monitHeaders = nil
+	})
+	flags := RootCmd.PersistentFlags()
+	if err := flags.Set("monit-header", "Accept=text/xml,application/xml"); err != nil {
+		t.Fatalf("Failed to set --monit-header: %v", err)
+	}
+	if len(monitHeaders) != 1 || monitHeaders[0] != "Accept=text/xml,application/xml" {
+		t.Errorf("Expected a single header value, got %v", monitHeaders)
+	}
+}
@@ -13,10 +13,11 @@ import (
 
 	_ "embed"
 
-	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/exporter-toolkit/web"
+	"github.com/ririnto/monit-exporter/internal/backoff"
 	"github.com/ririnto/monit-exporter/internal/config"
 	"github.com/ririnto/monit-exporter/internal/exporter"
+	"github.com/ririnto/monit-exporter/internal/monit"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
@@ -37,27 +38,57 @@ var serveCmd = &cobra.Command{
 		}
 		logrus.Debugf("Log level set to '%s'", logLevel)
 
-		cfg := &config.Config{
-			ListenAddress:  listenAddress,
-			MetricsPath:    metricsPath,
-			IgnoreSSL:      ignoreSSL,
-			MonitScrapeURI: monitScrapeURI,
-			MonitUser:      monitUser,
-			MonitPassword:  monitPassword,
-			LogLevel:       logLevel,
+		cfg, err := newConfig()
+		if err != nil {
+			logrus.Errorf("Invalid configuration: %v", err)
+			return err
 		}
 		logrus.Debugf("Server configuration loaded: %+v", cfg)
 
+		if err := web.Validate(cfg.WebConfigFile); err != nil {
+			logrus.Errorf("Invalid web configuration file '%s': %v", cfg.WebConfigFile, err)
+			return fmt.Errorf("invalid web configuration file: %w", err)
+		}
+
 		exp, err := exporter.NewExporter(cfg)
 		if err != nil {
 			logrus.Errorf("Failed to create exporter: %v", err)
 			return fmt.Errorf("failed to create exporter: %w", err)
 		}
-		logrus.Debug("Registering exporter to Prometheus")
-		prometheus.MustRegister(exp)
+		stopPriming := primeFetchState(exp, backoff.Backoff{Min: time.Second, Max: 30 * time.Second, Jitter: true})
+		defer stopPriming()
+
+		stopOTLP, err := startOTLP(context.Background(), exp)
+		if err != nil {
+			logrus.Errorf("Failed to start OTLP export: %v", err)
+			return err
+		}
+		defer stopOTLP()
+
+		stopNotifier, err := startNotifier(exp)
+		if err != nil {
+			logrus.Errorf("Failed to start notifier: %v", err)
+			return err
+		}
+		defer stopNotifier()
 
 		mux := http.NewServeMux()
-		mux.Handle(cfg.MetricsPath, promhttp.Handler())
+		mux.Handle(cfg.MetricsPath, metricsHandler(exp, scrapeTimeoutOffset))
+		mux.HandleFunc("/-/healthy", healthyHandler())
+		mux.HandleFunc("/-/ready", readyHandler(exp, cfg.ReadyWindow))
+		mux.HandleFunc("GET /api/v1/status", apiStatusHandler(exp))
+		mux.HandleFunc("GET /api/v1/services/{name}", apiServiceHandler(exp))
+		if cfg.ControlTokenFile != "" {
+			token, err := loadControlToken(cfg.ControlTokenFile)
+			if err != nil {
+				logrus.Errorf("Failed to load control token: %v", err)
+				return fmt.Errorf("failed to load control token: %w", err)
+			}
+			logrus.Info("Service control API enabled at POST /api/v1/services/{name}/{action}")
+			mux.HandleFunc("POST /api/v1/services/{name}/{action}", controlHandler(func(service, action string) error {
+				return monit.ControlService(cfg, service, action)
+			}, token))
+		}
 		mux.HandleFunc("/favicon.ico", func(w http.ResponseWriter, r *http.Request) {
 			if 0 < len(embeddedFavicon) {
 				w.Header().Set("Content-Type", "image/x-icon")
@@ -66,15 +97,7 @@ var serveCmd = &cobra.Command{
 				http.ServeFile(w, r, filepath.Join("static", "favicon.ico"))
 			}
 		})
-		mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-			logrus.Debugf("Root path request received from %s", r.RemoteAddr)
-			w.Header().Set("Content-Type", "text/html; charset=utf-8")
-			_, _ = fmt.Fprintf(
-				w,
-				`<html><head><title>Monit Exporter</title></head><body><h1>Monit Exporter</h1><p><a href="%s">Metrics</a></p></body></html>`,
-				cfg.MetricsPath,
-			)
-		})
+		mux.HandleFunc("/", dashboardHandler(cfg.MetricsPath))
 
 		server := &http.Server{Addr: cfg.ListenAddress, Handler: commonLogHandler(mux)}
 		shutdownCh := make(chan os.Signal, 1)
@@ -92,7 +115,13 @@ var serveCmd = &cobra.Command{
 		}()
 
 		logrus.Infof("Starting Monit Exporter on %s", cfg.ListenAddress)
-		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		systemdSocket := false
+		webFlags := &web.FlagConfig{
+			WebListenAddresses: &[]string{cfg.ListenAddress},
+			WebSystemdSocket:   &systemdSocket,
+			WebConfigFile:      &cfg.WebConfigFile,
+		}
+		if err := web.ListenAndServe(server, webFlags, newSlogLogger()); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			logrus.Errorf("Failed to start server: %v", err)
 			return fmt.Errorf("failed to start server: %w", err)
 		}
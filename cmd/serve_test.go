@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -60,3 +62,17 @@ func TestNewLoggingResponseWriter(t *testing.T) {
 		t.Errorf("Expected lrw.size=%d, got %d", len(testData), lrw.size)
 	}
 }
+
+func TestServeCmd_InvalidWebConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "web-config.yml")
+	if err := os.WriteFile(path, []byte("basic_auth_users:\n  admin: not-a-bcrypt-hash\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write web config: %v", err)
+	}
+	defer func() { webConfigFile = "" }()
+	webConfigFile = path
+
+	err := serveCmd.RunE(serveCmd, nil)
+	if err == nil {
+		t.Fatal("Expected an error for an invalid web configuration file, got nil")
+	}
+}
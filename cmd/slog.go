@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/sirupsen/logrus"
+)
+
+// logrusHandler is a slog.Handler that forwards records to the global logrus logger,
+// so libraries logging through slog share the exporter's log level and format.
+type logrusHandler struct {
+	fields logrus.Fields
+	group  string
+}
+
+// newSlogLogger returns a slog.Logger backed by logrus.
+func newSlogLogger() *slog.Logger {
+	return slog.New(&logrusHandler{fields: logrus.Fields{}})
+}
+
+// logrusLevel converts a slog.Level to the closest logrus.Level.
+func logrusLevel(level slog.Level) logrus.Level {
+	switch {
+	case level >= slog.LevelError:
+		return logrus.ErrorLevel
+	case level >= slog.LevelWarn:
+		return logrus.WarnLevel
+	case level >= slog.LevelInfo:
+		return logrus.InfoLevel
+	default:
+		return logrus.DebugLevel
+	}
+}
+
+// Enabled reports whether logrus would emit a record at the given level.
+func (h *logrusHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return logrus.IsLevelEnabled(logrusLevel(level))
+}
+
+// Handle converts the record attributes to logrus fields and logs the message.
+func (h *logrusHandler) Handle(_ context.Context, record slog.Record) error {
+	fields := make(logrus.Fields, len(h.fields)+record.NumAttrs())
+	for k, v := range h.fields {
+		fields[k] = v
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		fields[h.key(attr.Key)] = attr.Value.Any()
+		return true
+	})
+	logrus.WithFields(fields).Log(logrusLevel(record.Level), record.Message)
+	return nil
+}
+
+// WithAttrs returns a handler that always includes the given attributes.
+func (h *logrusHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	fields := make(logrus.Fields, len(h.fields)+len(attrs))
+	for k, v := range h.fields {
+		fields[k] = v
+	}
+	for _, attr := range attrs {
+		fields[h.key(attr.Key)] = attr.Value.Any()
+	}
+	return &logrusHandler{fields: fields, group: h.group}
+}
+
+// WithGroup returns a handler that prefixes subsequent attribute keys with name.
+func (h *logrusHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &logrusHandler{fields: h.fields, group: h.key(name)}
+}
+
+// key qualifies an attribute key with the current group, if any.
+func (h *logrusHandler) key(k string) string {
+	if h.group == "" {
+		return k
+	}
+	return h.group + "." + k
+}
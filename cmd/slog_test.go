@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestLogrusHandler_ForwardsRecords(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logrus.SetOutput(buf)
+	defer logrus.SetOutput(os.Stderr)
+	logrus.SetLevel(logrus.InfoLevel)
+
+	logger := newSlogLogger().With("component", "web").WithGroup("tls")
+	logger.Info("TLS is enabled.", "http2", false)
+	logger.Debug("should be filtered")
+
+	output := buf.String()
+	for _, want := range []string{"TLS is enabled.", "component=web", "tls.http2=false"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected log output to contain %q, got %q", want, output)
+		}
+	}
+	if strings.Contains(output, "should be filtered") {
+		t.Errorf("Expected debug record to be filtered at info level, got %q", output)
+	}
+}
+
+func TestLogrusHandler_Enabled(t *testing.T) {
+	logrus.SetLevel(logrus.WarnLevel)
+	defer logrus.SetLevel(logrus.InfoLevel)
+
+	h := &logrusHandler{}
+	if h.Enabled(context.Background(), slog.LevelInfo) {
+		t.Errorf("Expected info level to be disabled when logrus level is warn")
+	}
+	if !h.Enabled(context.Background(), slog.LevelError) {
+		t.Errorf("Expected error level to be enabled when logrus level is warn")
+	}
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/ririnto/monit-exporter/internal/config"
+	"github.com/ririnto/monit-exporter/internal/exporter"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	textfileDirectory string
+	textfileName      string
+	textfileInterval  time.Duration
+	textfileOnce      bool
+)
+
+var textfileCmd = &cobra.Command{
+	Use:   "textfile",
+	Short: "Periodically write metrics for the node_exporter textfile collector",
+	Long: "Periodically collect the Monit status and atomically write the exporter's metrics " +
+		"to a .prom file, so node_exporter's textfile collector can expose them without an extra listening port.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logrus.Debug("textfileCmd invoked: starting textfile writer")
+
+		if err := config.SetLogLevel(logLevel); err != nil {
+			logrus.Errorf("Failed to set log level: %v", err)
+			return fmt.Errorf("failed to set log level: %w", err)
+		}
+		if textfileDirectory == "" {
+			return errors.New("--textfile-directory is required")
+		}
+		if filepath.Ext(textfileName) != ".prom" {
+			return fmt.Errorf("--textfile-name '%s' must end in .prom", textfileName)
+		}
+		if textfileInterval <= 0 {
+			return errors.New("--interval must be positive")
+		}
+
+		cfg, err := newConfig()
+		if err != nil {
+			return err
+		}
+		exp, err := exporter.NewExporter(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to create exporter: %w", err)
+		}
+		path := filepath.Join(textfileDirectory, textfileName)
+
+		if textfileOnce {
+			return exp.WriteTextFile(path)
+		}
+
+		ctx, cancel := signalContext()
+		defer cancel()
+		logrus.Infof("Writing metrics to %s every %s", path, textfileInterval)
+		runPeriodically(ctx, textfileInterval, func(context.Context) {
+			if err := exp.WriteTextFile(path); err != nil {
+				logrus.Errorf("Failed to write textfile: %v", err)
+			}
+		})
+		logrus.Info("Textfile writer stopped")
+		return nil
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(textfileCmd)
+
+	textfileCmd.Flags().StringVar(&textfileDirectory, "textfile-directory", "",
+		"Directory watched by the node_exporter textfile collector (required).")
+	textfileCmd.Flags().StringVar(&textfileName, "textfile-name", "monit.prom",
+		"Name of the metrics file written to the textfile directory.")
+	textfileCmd.Flags().DurationVar(&textfileInterval, "interval", 30*time.Second,
+		"How often to collect Monit status and rewrite the metrics file.")
+	textfileCmd.Flags().BoolVar(&textfileOnce, "once", false,
+		"Write the metrics file once and exit.")
+}
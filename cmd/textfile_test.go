@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTextfileCmd_Once(t *testing.T) {
+	dir := t.TempDir()
+	statusPath := filepath.Join(dir, "status.xml")
+	if err := os.WriteFile(statusPath, []byte(dumpTestXML), 0o600); err != nil {
+		t.Fatalf("Failed to write fixture: %v", err)
+	}
+
+	previousURI := monitScrapeURI
+	defer func() {
+		monitScrapeURI = previousURI
+		textfileDirectory, textfileOnce = "", false
+	}()
+	monitScrapeURI = "file://" + statusPath
+	textfileDirectory = dir
+	textfileOnce = true
+
+	if err := textfileCmd.RunE(textfileCmd, nil); err != nil {
+		t.Fatalf("textfileCmd returned error: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "monit.prom"))
+	if err != nil {
+		t.Fatalf("Failed to read metrics file: %v", err)
+	}
+	if !strings.Contains(string(data), "monit_exporter_up 1") {
+		t.Errorf("Expected metrics in textfile, got:\n%s", data)
+	}
+}
+
+func TestTextfileCmd_InvalidFlags(t *testing.T) {
+	defer func() { textfileDirectory, textfileName = "", "monit.prom" }()
+
+	if err := textfileCmd.RunE(textfileCmd, nil); err == nil {
+		t.Error("Expected an error without --textfile-directory, got nil")
+	}
+
+	textfileDirectory = t.TempDir()
+	textfileName = "monit.txt"
+	if err := textfileCmd.RunE(textfileCmd, nil); err == nil {
+		t.Error("Expected an error for a name without .prom suffix, got nil")
+	}
+}
@@ -0,0 +1,48 @@
+package backoff
+
+import (
+	"context"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// Backoff computes jittered exponential delays between retry attempts.
+type Backoff struct {
+	// Min is the delay before the first retry.
+	Min time.Duration
+	// Max caps the delay between retries.
+	Max time.Duration
+	// Factor multiplies the delay after each attempt; values below 1 are treated as 2.
+	Factor float64
+	// Jitter randomizes each delay to between half and the full computed value.
+	Jitter bool
+}
+
+// Duration returns the delay before retry attempt (0 for the first retry).
+func (b Backoff) Duration(attempt int) time.Duration {
+	factor := b.Factor
+	if factor < 1 {
+		factor = 2
+	}
+	delay := float64(b.Min) * math.Pow(factor, float64(attempt))
+	if 0 < b.Max && float64(b.Max) < delay {
+		delay = float64(b.Max)
+	}
+	if b.Jitter {
+		delay = delay/2 + rand.Float64()*delay/2
+	}
+	return time.Duration(delay)
+}
+
+// Sleep waits for d or until ctx is done, returning the context error in the latter case.
+func Sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
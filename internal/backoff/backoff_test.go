@@ -0,0 +1,39 @@
+package backoff
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBackoff_Duration(t *testing.T) {
+	b := Backoff{Min: 100 * time.Millisecond, Max: time.Second}
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second}
+	for attempt, expected := range want {
+		if got := b.Duration(attempt); got != expected {
+			t.Errorf("Duration(%d) = %v, want %v", attempt, got, expected)
+		}
+	}
+}
+
+func TestBackoff_Jitter(t *testing.T) {
+	b := Backoff{Min: time.Second, Max: 10 * time.Second, Factor: 3, Jitter: true}
+	for i := 0; i < 100; i++ {
+		got := b.Duration(1)
+		if got < 1500*time.Millisecond || 3*time.Second < got {
+			t.Fatalf("Jittered Duration(1) = %v, want within [1.5s, 3s]", got)
+		}
+	}
+}
+
+func TestSleep_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := Sleep(ctx, time.Hour); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if err := Sleep(context.Background(), time.Millisecond); err != nil {
+		t.Errorf("Expected nil after sleeping, got %v", err)
+	}
+}
@@ -0,0 +1,100 @@
+package breaker
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrOpen is returned instead of contacting a target while the breaker is open.
+var ErrOpen = errors.New("circuit breaker is open")
+
+// State is the state of a Breaker.
+type State int
+
+const (
+	// Closed lets every request through.
+	Closed State = iota
+	// Open rejects requests until the cool-down has elapsed.
+	Open
+	// HalfOpen has a single trial request in flight after the cool-down.
+	HalfOpen
+)
+
+// String returns the lower-case name of the state.
+func (s State) String() string {
+	switch s {
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// Breaker opens after Threshold consecutive failures and rejects requests for Cooldown,
+// after which a single trial request decides whether it closes again. Other requests are
+// rejected while the trial is in flight, unless it has not been recorded within Cooldown.
+type Breaker struct {
+	// Threshold is the number of consecutive failures that opens the breaker; 0 disables it.
+	Threshold int
+	// Cooldown is how long the breaker stays open before allowing a trial request.
+	Cooldown time.Duration
+
+	mutex    sync.Mutex
+	failures int
+	since    time.Time
+	state    State
+	now      func() time.Time
+}
+
+// Allow reports whether a request may be sent. Once the cool-down has elapsed, the first
+// caller is let through as the trial request and the breaker moves to half-open.
+func (b *Breaker) Allow() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.Threshold <= 0 || b.state == Closed {
+		return true
+	}
+	if b.clock().Sub(b.since) < b.Cooldown {
+		return false
+	}
+	b.state = HalfOpen
+	b.since = b.clock()
+	return true
+}
+
+// Record updates the breaker with the outcome of a request.
+func (b *Breaker) Record(err error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.Threshold <= 0 {
+		return
+	}
+	if err == nil {
+		b.failures = 0
+		b.state = Closed
+		return
+	}
+	b.failures++
+	if b.state == HalfOpen || b.Threshold <= b.failures {
+		b.state = Open
+		b.since = b.clock()
+	}
+}
+
+// State returns the current state of the breaker.
+func (b *Breaker) State() State {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.state
+}
+
+// clock returns the current time, which tests may override.
+func (b *Breaker) clock() time.Time {
+	if b.now != nil {
+		return b.now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,81 @@
+package breaker
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBreaker_OpensAndRecovers(t *testing.T) {
+	t.Log("Testing the breaker state transitions")
+	now := time.Unix(1700000000, 0)
+	b := &Breaker{Threshold: 2, Cooldown: time.Minute, now: func() time.Time { return now }}
+	failure := errors.New("connection reset")
+
+	b.Record(failure)
+	if !b.Allow() || b.State() != Closed {
+		t.Fatalf("Expected the breaker to stay closed after 1 failure, got %s", b.State())
+	}
+	b.Record(failure)
+	if b.Allow() || b.State() != Open {
+		t.Fatalf("Expected the breaker to open after 2 failures, got %s", b.State())
+	}
+
+	now = now.Add(time.Minute)
+	if !b.Allow() || b.State() != HalfOpen {
+		t.Fatalf("Expected a trial request after the cool-down, got %s", b.State())
+	}
+	b.Record(failure)
+	if b.Allow() || b.State() != Open {
+		t.Fatalf("Expected a failed trial to reopen the breaker, got %s", b.State())
+	}
+
+	now = now.Add(time.Minute)
+	b.Allow()
+	b.Record(nil)
+	if !b.Allow() || b.State() != Closed {
+		t.Errorf("Expected a successful trial to close the breaker, got %s", b.State())
+	}
+}
+
+func TestBreaker_Disabled(t *testing.T) {
+	t.Log("Testing a breaker without a threshold")
+	b := &Breaker{}
+	for range 10 {
+		b.Record(errors.New("connection refused"))
+	}
+	if !b.Allow() || b.State() != Closed {
+		t.Errorf("Expected a disabled breaker to stay closed, got %s", b.State())
+	}
+}
+
+func TestBreaker_SingleTrial(t *testing.T) {
+	t.Log("Testing that only one trial request is let through")
+	now := time.Unix(1700000000, 0)
+	b := &Breaker{Threshold: 1, Cooldown: time.Minute, now: func() time.Time { return now }}
+	b.Record(errors.New("connection refused"))
+	now = now.Add(time.Minute)
+
+	var wg sync.WaitGroup
+	var allowed atomic.Int32
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if b.Allow() {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if allowed.Load() != 1 || b.State() != HalfOpen {
+		t.Fatalf("Expected a single trial in half-open state, got %d allowed in %s", allowed.Load(), b.State())
+	}
+
+	now = now.Add(time.Minute)
+	if !b.Allow() {
+		t.Error("Expected another trial once the unrecorded trial exceeded the cool-down")
+	}
+}
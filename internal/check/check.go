@@ -0,0 +1,177 @@
+package check
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ririnto/monit-exporter/internal/monit"
+)
+
+// State is a Nagios plugin state; its value is the plugin exit code.
+type State int
+
+const (
+	// OK means every evaluated service is healthy.
+	OK State = iota
+	// Warning means at least one service crossed a warning threshold.
+	Warning
+	// Critical means at least one service is failing or crossed a critical threshold.
+	Critical
+	// Unknown means the Monit status could not be evaluated.
+	Unknown
+)
+
+// String returns the Nagios name of the state.
+func (s State) String() string {
+	switch s {
+	case OK:
+		return "OK"
+	case Warning:
+		return "WARNING"
+	case Critical:
+		return "CRITICAL"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// ParseState converts a Nagios state name (case-insensitive) to a State.
+func ParseState(name string) (State, error) {
+	for _, state := range []State{OK, Warning, Critical, Unknown} {
+		if strings.EqualFold(name, state.String()) {
+			return state, nil
+		}
+	}
+	return Unknown, fmt.Errorf("invalid state: %s", name)
+}
+
+// Threshold holds the warning and critical limits for a value; zero disables a limit.
+type Threshold struct {
+	Warning  float64
+	Critical float64
+}
+
+// evaluate returns the state of value against the threshold.
+func (t Threshold) evaluate(value float64) State {
+	switch {
+	case t.Critical > 0 && value >= t.Critical:
+		return Critical
+	case t.Warning > 0 && value >= t.Warning:
+		return Warning
+	default:
+		return OK
+	}
+}
+
+// Options configures the evaluation of a Monit snapshot.
+type Options struct {
+	// Services restricts the evaluation to the named services; empty means all services.
+	Services []string
+	// UnmonitoredState is reported for services Monit does not currently monitor.
+	UnmonitoredState State
+
+	Filesystem Threshold
+	CPU        Threshold
+	Memory     Threshold
+	Response   Threshold
+}
+
+// Result is the outcome of evaluating a Monit snapshot.
+type Result struct {
+	State    State
+	Services int
+	Problems []string
+	Perfdata []string
+}
+
+// raise records a problem and escalates the result state if needed.
+func (r *Result) raise(state State, problem string) {
+	if state == OK {
+		return
+	}
+	if state > r.State {
+		r.State = state
+	}
+	r.Problems = append(r.Problems, fmt.Sprintf("%s %s", problem, state))
+}
+
+// perf appends a Nagios performance data item.
+func (r *Result) perf(label string, value float64, uom string, t Threshold, maxValue string) {
+	r.Perfdata = append(r.Perfdata, fmt.Sprintf("'%s'=%g%s;%s;%s;0;%s",
+		strings.ReplaceAll(label, "'", "''"), value, uom, limit(t.Warning), limit(t.Critical), maxValue))
+}
+
+// limit formats a threshold limit, leaving disabled limits empty.
+func limit(v float64) string {
+	if v <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%g", v)
+}
+
+// Evaluate checks service states and resource thresholds of a Monit snapshot.
+func Evaluate(m monit.Monit, opts Options) Result {
+	result := Result{State: OK}
+	seen := map[string]bool{}
+
+	for service := range slices.Values(m.Services) {
+		if 0 < len(opts.Services) && !slices.Contains(opts.Services, service.Name) {
+			continue
+		}
+		seen[service.Name] = true
+		result.Services++
+
+		switch {
+		case service.Monitor == 0:
+			result.raise(opts.UnmonitoredState, fmt.Sprintf("%s not monitored", service.Name))
+		case service.Status != 0:
+			result.raise(Critical, fmt.Sprintf("%s failed (%s)", service.Name, strings.Join(monit.StatusFailures(service.Status), ", ")))
+		}
+
+		if service.Block != nil {
+			result.raise(opts.Filesystem.evaluate(float64(service.Block.Percent)), fmt.Sprintf("%s block usage %.1f%%", service.Name, service.Block.Percent))
+			result.perf(service.Name+"_block", float64(service.Block.Percent), "%", opts.Filesystem, "100")
+		}
+		if service.Inode != nil {
+			result.raise(opts.Filesystem.evaluate(float64(service.Inode.Percent)), fmt.Sprintf("%s inode usage %.1f%%", service.Name, service.Inode.Percent))
+			result.perf(service.Name+"_inode", float64(service.Inode.Percent), "%", opts.Filesystem, "100")
+		}
+		if service.Port != nil {
+			result.raise(opts.Response.evaluate(float64(service.Port.Responsetime)), fmt.Sprintf("%s response time %.3fs", service.Name, service.Port.Responsetime))
+			result.perf(service.Name+"_response", float64(service.Port.Responsetime), "s", opts.Response, "")
+		}
+		if service.System != nil {
+			cpu := float64(service.System.CPU.User + service.System.CPU.System + service.System.CPU.Wait)
+			result.raise(opts.CPU.evaluate(cpu), fmt.Sprintf("%s CPU usage %.1f%%", service.Name, cpu))
+			result.raise(opts.Memory.evaluate(float64(service.System.Memory.Percent)), fmt.Sprintf("%s memory usage %.1f%%", service.Name, service.System.Memory.Percent))
+			result.perf(service.Name+"_load1", float64(service.System.Load.Avg01), "", Threshold{}, "")
+			result.perf(service.Name+"_cpu", cpu, "%", opts.CPU, "100")
+			result.perf(service.Name+"_memory", float64(service.System.Memory.Percent), "%", opts.Memory, "100")
+			result.perf(service.Name+"_swap", float64(service.System.Swap.Percent), "%", Threshold{}, "100")
+		}
+	}
+
+	for name := range slices.Values(opts.Services) {
+		if !seen[name] {
+			result.raise(Critical, fmt.Sprintf("%s missing", name))
+		}
+	}
+	return result
+}
+
+// String formats the result as a single Nagios plugin output line with performance data.
+func (r Result) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "MONIT %s - ", r.State)
+	if len(r.Problems) == 0 {
+		fmt.Fprintf(&b, "%d services OK", r.Services)
+	} else {
+		fmt.Fprintf(&b, "%d problems in %d services: %s", len(r.Problems), r.Services, strings.Join(r.Problems, "; "))
+	}
+	if 0 < len(r.Perfdata) {
+		b.WriteString(" | ")
+		b.WriteString(strings.Join(r.Perfdata, " "))
+	}
+	return b.String()
+}
@@ -0,0 +1,96 @@
+package check
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ririnto/monit-exporter/internal/monit"
+)
+
+func TestEvaluate_AllOK(t *testing.T) {
+	m := monit.Monit{Services: []monit.Service{
+		{Type: 0, Name: "rootfs", Monitor: 1, Block: &monit.Block{Percent: 42.5}},
+		{Type: 3, Name: "nginx", Monitor: 1},
+	}}
+	result := Evaluate(m, Options{Filesystem: Threshold{Warning: 80, Critical: 90}})
+
+	if result.State != OK {
+		t.Errorf("Expected OK, got %s (%v)", result.State, result.Problems)
+	}
+	want := "MONIT OK - 2 services OK | 'rootfs_block'=42.5%;80;90;0;100"
+	if got := result.String(); got != want {
+		t.Errorf("Expected output %q, got %q", want, got)
+	}
+}
+
+func TestEvaluate_States(t *testing.T) {
+	tests := []struct {
+		name    string
+		service monit.Service
+		opts    Options
+		want    State
+	}{
+		{
+			name:    "failed service",
+			service: monit.Service{Name: "nginx", Monitor: 1, Status: 0x200},
+			want:    Critical,
+		},
+		{
+			name:    "unmonitored service",
+			service: monit.Service{Name: "nginx", Monitor: 0},
+			opts:    Options{UnmonitoredState: Warning},
+			want:    Warning,
+		},
+		{
+			name:    "filesystem warning",
+			service: monit.Service{Name: "rootfs", Monitor: 1, Inode: &monit.Inode{Percent: 85}},
+			opts:    Options{Filesystem: Threshold{Warning: 80, Critical: 90}},
+			want:    Warning,
+		},
+		{
+			name:    "response critical",
+			service: monit.Service{Name: "web", Monitor: 1, Port: &monit.Port{Responsetime: 2.5}},
+			opts:    Options{Response: Threshold{Warning: 1, Critical: 2}},
+			want:    Critical,
+		},
+		{
+			name: "memory threshold disabled",
+			service: monit.Service{Name: "host", Monitor: 1, System: &monit.System{
+				Memory: monit.Memory{Percent: 99},
+			}},
+			want: OK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Evaluate(monit.Monit{Services: []monit.Service{tt.service}}, tt.opts)
+			if result.State != tt.want {
+				t.Errorf("Expected %s, got %s (%v)", tt.want, result.State, result.Problems)
+			}
+		})
+	}
+}
+
+func TestEvaluate_MissingService(t *testing.T) {
+	m := monit.Monit{Services: []monit.Service{{Name: "nginx", Monitor: 1}, {Name: "cron", Monitor: 1, Status: 0x200}}}
+	result := Evaluate(m, Options{Services: []string{"nginx", "postgres"}})
+
+	if result.State != Critical {
+		t.Errorf("Expected CRITICAL, got %s", result.State)
+	}
+	if result.Services != 1 {
+		t.Errorf("Expected 1 evaluated service, got %d", result.Services)
+	}
+	if !strings.Contains(result.String(), "postgres missing") {
+		t.Errorf("Expected missing service in output, got %q", result.String())
+	}
+}
+
+func TestParseState(t *testing.T) {
+	if state, err := ParseState("warning"); err != nil || state != Warning {
+		t.Errorf("Expected WARNING, got %s (err=%v)", state, err)
+	}
+	if _, err := ParseState("bogus"); err == nil {
+		t.Error("Expected an error for an invalid state, got nil")
+	}
+}
@@ -1,18 +1,35 @@
 package config
 
 import (
+	"time"
+
 	"github.com/sirupsen/logrus"
 )
 
 // Config holds the configuration values needed by the Monit Exporter.
 type Config struct {
-	ListenAddress  string
-	MetricsPath    string
-	IgnoreSSL      bool
-	MonitScrapeURI string
-	MonitUser      string
-	MonitPassword  string
-	LogLevel       string
+	ListenAddress    string
+	MetricsPath      string
+	IgnoreSSL        bool
+	MonitScrapeURI   string
+	MonitUser        string
+	MonitPassword    string
+	LogLevel         string
+	WebConfigFile    string
+	ReadyWindow      time.Duration
+	ControlTokenFile string
+	MaxBodySize      int64
+	ScrapeTimeout    time.Duration
+	MaxRetries       int
+	RetryMinBackoff  time.Duration
+	RetryMaxBackoff  time.Duration
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+	GracePeriod      time.Duration
+
+	MonitProxyURL        string
+	MonitHeaders         map[string]string
+	MonitBearerTokenFile string
 }
 
 // SetLogLevel sets the global log level of logrus based on the given string.
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTLSOptions_ClientConfig_Default(t *testing.T) {
+	tlsConfig, err := TLSOptions{InsecureSkipVerify: true}.ClientConfig()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !tlsConfig.InsecureSkipVerify {
+		t.Error("Expected InsecureSkipVerify to be set")
+	}
+	if tlsConfig.RootCAs != nil || len(tlsConfig.Certificates) != 0 {
+		t.Error("Expected system roots and no client certificate")
+	}
+}
+
+func TestTLSOptions_ClientConfig_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	bogus := filepath.Join(dir, "bogus.pem")
+	if err := os.WriteFile(bogus, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	tests := map[string]TLSOptions{
+		"missing CA file":  {CAFile: filepath.Join(dir, "missing.pem")},
+		"invalid CA file":  {CAFile: bogus},
+		"cert without key": {CertFile: bogus},
+		"invalid key pair": {CertFile: bogus, KeyFile: bogus},
+	}
+	for name, opts := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := opts.ClientConfig(); err == nil {
+				t.Error("Expected an error, got nil")
+			}
+		})
+	}
+}
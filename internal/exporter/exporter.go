@@ -1,12 +1,16 @@
 package exporter
 
 import (
+	"context"
 	"errors"
 	"slices"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/ririnto/monit-exporter/internal/backoff"
+	"github.com/ririnto/monit-exporter/internal/breaker"
 	"github.com/ririnto/monit-exporter/internal/config"
 	"github.com/ririnto/monit-exporter/internal/monit"
 	"github.com/sirupsen/logrus"
@@ -17,21 +21,27 @@ const (
 )
 
 var (
+	// serviceLabelNames are the labels of every per-service metric.
+	serviceLabelNames = []string{"service_name", "service_type", "service_monitor_status"}
+
 	// ErrNilConfig is returned when a nil config is provided to NewExporter.
 	ErrNilConfig = errors.New("config is nil")
 )
 
-// serviceTypes maps Monit service type integers to descriptive strings.
-var serviceTypes = map[int]string{
-	0: "Filesystem",
-	1: "Directory",
-	2: "File",
-	3: "Process",
-	4: "Remote host",
-	5: "System",
-	6: "Fifo",
-	7: "Program",
-	8: "Network",
+// FetchState describes the outcome of the most recent Monit fetches.
+type FetchState struct {
+	LastAttempt time.Time
+	LastSuccess time.Time
+	LastError   error
+}
+
+// Descriptor describes a metric family produced by the Exporter.
+type Descriptor struct {
+	Name   string
+	Help   string
+	Labels []string
+	// Type is prometheus.GaugeValue or prometheus.CounterValue.
+	Type prometheus.ValueType
 }
 
 // Exporter collects Monit metrics and exposes them to Prometheus.
@@ -39,8 +49,26 @@ type Exporter struct {
 	cfg   *config.Config
 	mutex sync.Mutex
 
-	up     prometheus.Gauge
-	status *prometheus.GaugeVec
+	stateMutex sync.RWMutex
+	state      FetchState
+
+	descriptors []Descriptor
+
+	backoff backoff.Backoff
+	breaker *breaker.Breaker
+
+	lastGood   monit.Monit
+	lastGoodAt time.Time
+
+	up                 prometheus.Gauge
+	malformed          prometheus.Gauge
+	retries            prometheus.Counter
+	breakerState       prometheus.Gauge
+	snapshotStale      prometheus.Gauge
+	snapshotAge        prometheus.Gauge
+	status             *prometheus.GaugeVec
+	failure            *prometheus.GaugeVec
+	collectedTimestamp *prometheus.GaugeVec
 
 	blockUsage   *prometheus.GaugeVec
 	blockTotal   *prometheus.GaugeVec
@@ -50,7 +78,8 @@ type Exporter struct {
 	inodeTotal   *prometheus.GaugeVec
 	inodePercent *prometheus.GaugeVec
 
-	portResponseTime *prometheus.GaugeVec
+	portResponseTime     *prometheus.GaugeVec
+	certificateValidDays *prometheus.GaugeVec
 
 	systemLoadAvg01 *prometheus.GaugeVec
 	systemLoadAvg05 *prometheus.GaugeVec
@@ -76,285 +105,282 @@ func NewExporter(cfg *config.Config) (*Exporter, error) {
 	logrus.Debugf("NewExporter: creating exporter with ListenAddress=%s, MonitScrapeURI=%s",
 		cfg.ListenAddress, cfg.MonitScrapeURI)
 
-	labelNames := []string{"service_name", "service_type", "service_monitor_status"}
-
-	return &Exporter{
-		cfg: cfg,
-
-		up: prometheus.NewGauge(prometheus.GaugeOpts{
-			Namespace: namespace,
-			Name:      "exporter_up",
-			Help:      "Indicates whether the Monit endpoint is reachable (1) or not (0).",
-		}),
-		status: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace: namespace,
-				Name:      "exporter_service_check",
-				Help:      "Indicates the status field from Monit.",
-			},
-			labelNames,
-		),
-
-		blockUsage: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace: namespace,
-				Name:      "service_block_usage_bytes",
-				Help:      "Block usage for filesystem-based services.",
-			},
-			labelNames,
-		),
-		blockTotal: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace: namespace,
-				Name:      "service_block_total_bytes",
-				Help:      "Block total capacity for filesystem-based services.",
-			},
-			labelNames,
-		),
-		blockPercent: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace: namespace,
-				Name:      "service_block_usage_percent",
-				Help:      "Block usage percentage for filesystem-based services.",
-			},
-			labelNames,
-		),
-
-		inodeUsage: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace: namespace,
-				Name:      "service_inode_usage",
-				Help:      "Inode usage for filesystem-based services.",
-			},
-			labelNames,
-		),
-		inodeTotal: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace: namespace,
-				Name:      "service_inode_total",
-				Help:      "Total number of inodes for filesystem-based services.",
-			},
-			labelNames,
-		),
-		inodePercent: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace: namespace,
-				Name:      "service_inode_usage_percent",
-				Help:      "Inode usage percentage for filesystem-based services.",
-			},
-			labelNames,
-		),
-
-		portResponseTime: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace: namespace,
-				Name:      "service_port_response_seconds",
-				Help:      "Response time in seconds for port-based checks.",
-			},
-			labelNames,
-		),
-
-		systemLoadAvg01: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace: namespace,
-				Name:      "service_system_loadavg_01",
-				Help:      "1-minute load average for system-based services.",
-			},
-			labelNames,
-		),
-		systemLoadAvg05: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace: namespace,
-				Name:      "service_system_loadavg_05",
-				Help:      "5-minute load average for system-based services.",
-			},
-			labelNames,
-		),
-		systemLoadAvg15: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace: namespace,
-				Name:      "service_system_loadavg_15",
-				Help:      "15-minute load average for system-based services.",
-			},
-			labelNames,
-		),
-
-		systemCPUUser: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace: namespace,
-				Name:      "service_system_cpu_user_percent",
-				Help:      "CPU usage in user space (percent).",
-			},
-			labelNames,
-		),
-		systemCPUSystem: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace: namespace,
-				Name:      "service_system_cpu_system_percent",
-				Help:      "CPU usage in kernel space (percent).",
-			},
-			labelNames,
-		),
-		systemCPUWait: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace: namespace,
-				Name:      "service_system_cpu_wait_percent",
-				Help:      "CPU usage waiting for I/O (percent).",
-			},
-			labelNames,
-		),
-
-		systemMemPercent: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace: namespace,
-				Name:      "service_system_memory_usage_percent",
-				Help:      "Memory usage percentage for system-based services.",
-			},
-			labelNames,
-		),
-		systemMemKilobytes: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace: namespace,
-				Name:      "service_system_memory_usage_kilobytes",
-				Help:      "Memory usage in kilobytes for system-based services.",
-			},
-			labelNames,
-		),
-		systemSwapPercent: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace: namespace,
-				Name:      "service_system_swap_usage_percent",
-				Help:      "Swap usage percentage for system-based services.",
-			},
-			labelNames,
-		),
-		systemSwapKilobytes: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace: namespace,
-				Name:      "service_system_swap_usage_kilobytes",
-				Help:      "Swap usage in kilobytes for system-based services.",
-			},
-			labelNames,
-		),
-	}, nil
-}
+	e := &Exporter{
+		cfg:     cfg,
+		backoff: backoff.Backoff{Min: cfg.RetryMinBackoff, Max: cfg.RetryMaxBackoff, Jitter: true},
+		breaker: &breaker.Breaker{Threshold: cfg.BreakerThreshold, Cooldown: cfg.BreakerCooldown},
+	}
 
-// Describe sends the descriptors of each metric to the provided channel.
-func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
-	e.up.Describe(ch)
-	e.status.Describe(ch)
+	e.up = e.newGauge("exporter_up", "Indicates whether the Monit endpoint is reachable (1) or not (0).")
+	e.malformed = e.newGauge("exporter_malformed_services", "Number of services skipped in the last scrape because they could not be parsed.")
+	e.retries = e.newCounter("exporter_fetch_retries_total", "Total number of Monit fetch attempts that were retried.")
+	e.breakerState = e.newGauge("exporter_circuit_breaker_state", "State of the Monit circuit breaker (0 closed, 1 open, 2 half-open).")
+	e.snapshotStale = e.newGauge("exporter_snapshot_stale", "Set to 1 while the last good Monit snapshot is served after a failed fetch.")
+	e.snapshotAge = e.newGauge("exporter_snapshot_age_seconds", "Age in seconds of the Monit snapshot the metrics are based on.")
+	e.status = e.newGaugeVec("exporter_service_check", "Indicates the status field from Monit.")
+	e.failure = e.newGaugeVec("service_failure", "Set to 1 for each failure bit in the Monit status of a service.", "failure")
+	e.collectedTimestamp = e.newGaugeVec("service_collected_timestamp_seconds", "Unix time at which Monit last collected the service.")
+	e.blockUsage = e.newGaugeVec("service_block_usage_bytes", "Block usage for filesystem-based services.")
+	e.blockTotal = e.newGaugeVec("service_block_total_bytes", "Block total capacity for filesystem-based services.")
+	e.blockPercent = e.newGaugeVec("service_block_usage_percent", "Block usage percentage for filesystem-based services.")
+	e.inodeUsage = e.newGaugeVec("service_inode_usage", "Inode usage for filesystem-based services.")
+	e.inodeTotal = e.newGaugeVec("service_inode_total", "Total number of inodes for filesystem-based services.")
+	e.inodePercent = e.newGaugeVec("service_inode_usage_percent", "Inode usage percentage for filesystem-based services.")
+	e.portResponseTime = e.newGaugeVec("service_port_response_seconds", "Response time in seconds for port-based checks.")
+	e.certificateValidDays = e.newGaugeVec("service_port_certificate_valid_days", "Days until the TLS certificate of a port check expires.")
+	e.systemLoadAvg01 = e.newGaugeVec("service_system_loadavg_01", "1-minute load average for system-based services.")
+	e.systemLoadAvg05 = e.newGaugeVec("service_system_loadavg_05", "5-minute load average for system-based services.")
+	e.systemLoadAvg15 = e.newGaugeVec("service_system_loadavg_15", "15-minute load average for system-based services.")
+	e.systemCPUUser = e.newGaugeVec("service_system_cpu_user_percent", "CPU usage in user space (percent).")
+	e.systemCPUSystem = e.newGaugeVec("service_system_cpu_system_percent", "CPU usage in kernel space (percent).")
+	e.systemCPUWait = e.newGaugeVec("service_system_cpu_wait_percent", "CPU usage waiting for I/O (percent).")
+	e.systemMemPercent = e.newGaugeVec("service_system_memory_usage_percent", "Memory usage percentage for system-based services.")
+	e.systemMemKilobytes = e.newGaugeVec("service_system_memory_usage_kilobytes", "Memory usage in kilobytes for system-based services.")
+	e.systemSwapPercent = e.newGaugeVec("service_system_swap_usage_percent", "Swap usage percentage for system-based services.")
+	e.systemSwapKilobytes = e.newGaugeVec("service_system_swap_usage_kilobytes", "Swap usage in kilobytes for system-based services.")
+
+	return e, nil
+}
 
-	e.blockUsage.Describe(ch)
-	e.blockTotal.Describe(ch)
-	e.blockPercent.Describe(ch)
+// newGauge creates an unlabelled gauge and records its descriptor.
+func (e *Exporter) newGauge(name, help string) prometheus.Gauge {
+	e.descriptors = append(e.descriptors, Descriptor{Name: namespace + "_" + name, Help: help, Type: prometheus.GaugeValue})
+	return prometheus.NewGauge(prometheus.GaugeOpts{Namespace: namespace, Name: name, Help: help})
+}
 
-	e.inodeUsage.Describe(ch)
-	e.inodeTotal.Describe(ch)
-	e.inodePercent.Describe(ch)
+// newCounter creates an unlabelled counter and records its descriptor.
+func (e *Exporter) newCounter(name, help string) prometheus.Counter {
+	e.descriptors = append(e.descriptors, Descriptor{Name: namespace + "_" + name, Help: help, Type: prometheus.CounterValue})
+	return prometheus.NewCounter(prometheus.CounterOpts{Namespace: namespace, Name: name, Help: help})
+}
 
-	e.portResponseTime.Describe(ch)
+// newGaugeVec creates a per-service gauge vector, with optional extra labels, and records its descriptor.
+func (e *Exporter) newGaugeVec(name, help string, extraLabels ...string) *prometheus.GaugeVec {
+	labels := append(slices.Clone(serviceLabelNames), extraLabels...)
+	e.descriptors = append(e.descriptors, Descriptor{Name: namespace + "_" + name, Help: help, Labels: labels, Type: prometheus.GaugeValue})
+	return prometheus.NewGaugeVec(prometheus.GaugeOpts{Namespace: namespace, Name: name, Help: help}, labels)
+}
 
-	e.systemLoadAvg01.Describe(ch)
-	e.systemLoadAvg05.Describe(ch)
-	e.systemLoadAvg15.Describe(ch)
+// Descriptors returns the metric families the Exporter produces, in exposition order.
+func (e *Exporter) Descriptors() []Descriptor {
+	return slices.Clone(e.descriptors)
+}
 
-	e.systemCPUUser.Describe(ch)
-	e.systemCPUSystem.Describe(ch)
-	e.systemCPUWait.Describe(ch)
+// vectors returns the per-service metric vectors in exposition order.
+func (e *Exporter) vectors() []*prometheus.GaugeVec {
+	return []*prometheus.GaugeVec{
+		e.status,
+		e.failure,
+		e.collectedTimestamp,
+		e.blockUsage,
+		e.blockTotal,
+		e.blockPercent,
+		e.inodeUsage,
+		e.inodeTotal,
+		e.inodePercent,
+		e.portResponseTime,
+		e.certificateValidDays,
+		e.systemLoadAvg01,
+		e.systemLoadAvg05,
+		e.systemLoadAvg15,
+		e.systemCPUUser,
+		e.systemCPUSystem,
+		e.systemCPUWait,
+		e.systemMemPercent,
+		e.systemMemKilobytes,
+		e.systemSwapPercent,
+		e.systemSwapKilobytes,
+	}
+}
 
-	e.systemMemPercent.Describe(ch)
-	e.systemMemKilobytes.Describe(ch)
-	e.systemSwapPercent.Describe(ch)
-	e.systemSwapKilobytes.Describe(ch)
+// Describe sends the descriptors of each metric to the provided channel.
+func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
+	e.up.Describe(ch)
+	e.malformed.Describe(ch)
+	e.retries.Describe(ch)
+	e.breakerState.Describe(ch)
+	e.snapshotStale.Describe(ch)
+	e.snapshotAge.Describe(ch)
+	for vec := range slices.Values(e.vectors()) {
+		vec.Describe(ch)
+	}
 
 	logrus.Debug("Exporter.Describe: described all metrics to the channel")
 }
 
 // Collect is called by the Prometheus registry to gather metrics.
 func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
+	e.collect(context.Background(), ch)
+}
+
+// contextCollector collects an Exporter with the Monit fetch bounded by ctx.
+type contextCollector struct {
+	e   *Exporter
+	ctx context.Context
+}
+
+// Describe sends the descriptors of the Exporter.
+func (c contextCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.e.Describe(ch)
+}
+
+// Collect gathers the metrics of the Exporter within the deadline of ctx.
+func (c contextCollector) Collect(ch chan<- prometheus.Metric) {
+	c.e.collect(c.ctx, ch)
+}
+
+// WithContext returns a collector for the Exporter whose Monit fetch, including retries,
+// is bounded by ctx, such as the deadline of a single Prometheus scrape.
+func (e *Exporter) WithContext(ctx context.Context) prometheus.Collector {
+	return contextCollector{e: e, ctx: ctx}
+}
+
+// collect scrapes Monit within ctx and sends the metrics to ch.
+func (e *Exporter) collect(ctx context.Context, ch chan<- prometheus.Metric) {
 	e.mutex.Lock()
 	defer e.mutex.Unlock()
 
 	logrus.Debug("Exporter.Collect: resetting metrics before scrape")
 
-	e.status.Reset()
-	e.blockUsage.Reset()
-	e.blockTotal.Reset()
-	e.blockPercent.Reset()
-	e.inodeUsage.Reset()
-	e.inodeTotal.Reset()
-	e.inodePercent.Reset()
-	e.portResponseTime.Reset()
-	e.systemLoadAvg01.Reset()
-	e.systemLoadAvg05.Reset()
-	e.systemLoadAvg15.Reset()
-	e.systemCPUUser.Reset()
-	e.systemCPUSystem.Reset()
-	e.systemCPUWait.Reset()
-	e.systemMemPercent.Reset()
-	e.systemMemKilobytes.Reset()
-	e.systemSwapPercent.Reset()
-	e.systemSwapKilobytes.Reset()
-
-	err := e.scrape()
+	for vec := range slices.Values(e.vectors()) {
+		vec.Reset()
+	}
+
+	err := e.scrape(ctx)
 	if err != nil {
 		logrus.Errorf("Exporter.Collect: scrape error: %v", err)
 	}
 
 	e.up.Collect(ch)
-	e.status.Collect(ch)
-	e.blockUsage.Collect(ch)
-	e.blockTotal.Collect(ch)
-	e.blockPercent.Collect(ch)
-	e.inodeUsage.Collect(ch)
-	e.inodeTotal.Collect(ch)
-	e.inodePercent.Collect(ch)
-	e.portResponseTime.Collect(ch)
-	e.systemLoadAvg01.Collect(ch)
-	e.systemLoadAvg05.Collect(ch)
-	e.systemLoadAvg15.Collect(ch)
-	e.systemCPUUser.Collect(ch)
-	e.systemCPUSystem.Collect(ch)
-	e.systemCPUWait.Collect(ch)
-	e.systemMemPercent.Collect(ch)
-	e.systemMemKilobytes.Collect(ch)
-	e.systemSwapPercent.Collect(ch)
-	e.systemSwapKilobytes.Collect(ch)
+	e.malformed.Collect(ch)
+	e.retries.Collect(ch)
+	e.breakerState.Collect(ch)
+	e.snapshotStale.Collect(ch)
+	e.snapshotAge.Collect(ch)
+	for vec := range slices.Values(e.vectors()) {
+		vec.Collect(ch)
+	}
 
 	logrus.Debug("Exporter.Collect: metrics collected and sent to the channel")
 }
 
-// scrape fetches Monit status and updates the metrics.
-func (e *Exporter) scrape() error {
-	logrus.Debug("Exporter.scrape: fetching Monit status")
-	data, err := monit.FetchMonitStatus(e.cfg)
+// State returns the outcome of the most recent Monit fetches without contacting Monit.
+func (e *Exporter) State() FetchState {
+	e.stateMutex.RLock()
+	defer e.stateMutex.RUnlock()
+	return e.state
+}
+
+// recordFetch stores the outcome of a Monit fetch attempt.
+func (e *Exporter) recordFetch(at time.Time, err error) {
+	e.stateMutex.Lock()
+	defer e.stateMutex.Unlock()
+	e.state.LastAttempt = at
+	e.state.LastError = err
+	if err == nil {
+		e.state.LastSuccess = at
+	}
+}
+
+// Fetch retrieves and parses the Monit status, recording the outcome in the fetch state.
+// It does not update any per-service metrics, so it can back other consumers of the Monit snapshot.
+func (e *Exporter) Fetch() (monit.Monit, error) {
+	return e.FetchContext(context.Background())
+}
+
+// FetchContext is Fetch with the fetch, including retries, bounded by ctx. Without a
+// deadline on ctx the configured scrape timeout applies.
+func (e *Exporter) FetchContext(ctx context.Context) (monit.Monit, error) {
+	start := time.Now()
+	parsed, err := e.fetchWithRetries(ctx)
 	if err != nil {
-		logrus.Warnf("Exporter.scrape: failed to fetch Monit status: %v", err)
-		e.up.Set(0)
-		e.status.Reset()
-		return err
+		logrus.Warnf("Exporter.Fetch: failed to fetch Monit status: %v", err)
+		e.recordFetch(start, err)
+		return monit.Monit{}, err
+	}
+	logrus.Debug("Exporter.Fetch: successfully fetched and parsed Monit status")
+	e.recordFetch(start, nil)
+	return parsed, nil
+}
+
+// fetchWithRetries fetches the Monit status, retrying failed attempts with jittered exponential
+// backoff as long as the next attempt can still start before the scrape deadline. The circuit
+// breaker records the outcome of the whole fetch and short-circuits fetches while it is open.
+func (e *Exporter) fetchWithRetries(ctx context.Context) (monit.Monit, error) {
+	defer func() { e.breakerState.Set(float64(e.breaker.State())) }()
+	if !e.breaker.Allow() {
+		logrus.Debug("Exporter.fetchWithRetries: circuit breaker is open, skipping fetch")
+		return monit.Monit{}, breaker.ErrOpen
+	}
+
+	if _, ok := ctx.Deadline(); !ok && 0 < e.cfg.ScrapeTimeout {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.cfg.ScrapeTimeout)
+		defer cancel()
 	}
-	logrus.Debugf("Exporter.scrape: successfully fetched Monit status (%d bytes)", len(data))
 
-	parsed, err := monit.ParseMonitStatus(data)
+	for attempt := 0; ; attempt++ {
+		parsed, err := monit.StreamMonitStatusContext(ctx, e.cfg)
+		if err == nil || errors.Is(err, monit.ErrResponseTooLarge) || e.cfg.MaxRetries <= attempt {
+			e.breaker.Record(err)
+			return parsed, err
+		}
+		delay := e.backoff.Duration(attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= delay {
+			logrus.Debugf("Exporter.fetchWithRetries: no time left for retry %d before the scrape deadline", attempt+1)
+			e.breaker.Record(err)
+			return parsed, err
+		}
+		logrus.Warnf("Exporter.fetchWithRetries: attempt %d failed, retrying in %s: %v", attempt+1, delay, err)
+		e.retries.Inc()
+		if backoff.Sleep(ctx, delay) != nil {
+			e.breaker.Record(err)
+			return monit.Monit{}, err
+		}
+	}
+}
+
+// scrape fetches Monit status and updates the metrics. When the fetch fails within the
+// grace period of the last good snapshot, that snapshot is served again and flagged as stale.
+func (e *Exporter) scrape(ctx context.Context) error {
+	logrus.Debug("Exporter.scrape: fetching Monit status")
+	parsed, err := e.FetchContext(ctx)
 	if err != nil {
-		logrus.Warnf("Exporter.scrape: failed to parse Monit status: %v", err)
+		age := time.Since(e.lastGoodAt)
+		if !e.lastGoodAt.IsZero() && age <= e.cfg.GracePeriod {
+			logrus.Warnf("Exporter.scrape: serving the last good snapshot from %s ago", age.Round(time.Second))
+			e.update(e.lastGood)
+			e.snapshotStale.Set(1)
+			e.snapshotAge.Set(age.Seconds())
+		} else {
+			e.status.Reset()
+			e.snapshotStale.Set(0)
+			e.snapshotAge.Set(0)
+		}
 		e.up.Set(0)
-		e.status.Reset()
 		return err
 	}
-	logrus.Debug("Exporter.scrape: successfully parsed Monit status")
 
+	e.lastGood, e.lastGoodAt = parsed, time.Now()
+	e.update(parsed)
+	e.snapshotStale.Set(0)
+	e.snapshotAge.Set(0)
+	return nil
+}
+
+// update sets the metrics from a parsed Monit snapshot.
+func (e *Exporter) update(parsed monit.Monit) {
 	e.up.Set(1)
-	logrus.Debug("Exporter.scrape: set exporter_up to 1 (Monit is reachable)")
+	logrus.Debug("Exporter.update: set exporter_up to 1 (Monit is reachable)")
+	e.malformed.Set(float64(parsed.Malformed))
 
 	for service := range slices.Values(parsed.Services) {
-		serviceType, ok := serviceTypes[service.Type]
+		serviceType, ok := monit.ServiceTypeName(service.Type)
 		if !ok {
 			serviceType = "unknown"
-			logrus.Warnf("Exporter.scrape: unknown service service_type=%d, serviceNameservice_name=%s", service.Type, service.Name)
+			logrus.Warnf("Exporter.update: unknown service service_type=%d, service_name=%s", service.Type, service.Name)
 		}
-		serviceMonitorStatus := strconv.Itoa(service.Monitor)
+		serviceMonitorStatus := strconv.FormatInt(int64(service.Monitor), 10)
 
 		e.status.With(prometheus.Labels{
 			"service_name":           service.Name,
@@ -363,7 +389,7 @@ func (e *Exporter) scrape() error {
 		}).Set(float64(service.Status))
 
 		logrus.Debugf(
-			"Exporter.scrape: service_name=%s, service_type=%s, service_monitor_status=%d, service_status=%d",
+			"Exporter.update: service_name=%s, service_type=%s, service_monitor_status=%d, service_status=%d",
 			service.Name,
 			serviceType,
 			service.Monitor,
@@ -372,7 +398,31 @@ func (e *Exporter) scrape() error {
 
 		e.collectServiceMetrics(service, serviceType, serviceMonitorStatus)
 	}
-	return nil
+}
+
+// ServiceMetricNames returns the names of the metrics the Exporter produces for a single service.
+func ServiceMetricNames(service monit.Service) ([]string, error) {
+	e, err := NewExporter(&config.Config{})
+	if err != nil {
+		return nil, err
+	}
+	e.update(monit.Monit{Services: []monit.Service{service}})
+
+	registry := prometheus.NewRegistry()
+	for vec := range slices.Values(e.vectors()) {
+		if err := registry.Register(vec); err != nil {
+			return nil, err
+		}
+	}
+	families, err := registry.Gather()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(families))
+	for family := range slices.Values(families) {
+		names = append(names, family.GetName())
+	}
+	return names, nil
 }
 
 // collectServiceMetrics updates detailed metrics for a single Monit service.
@@ -383,34 +433,50 @@ func (e *Exporter) collectServiceMetrics(service monit.Service, serviceType, ser
 		"service_monitor_status": serviceMonitorStatus,
 	}
 
+	for failure := range slices.Values(monit.StatusFailures(service.Status)) {
+		e.failure.With(prometheus.Labels{
+			"service_name":           service.Name,
+			"service_type":           serviceType,
+			"service_monitor_status": serviceMonitorStatus,
+			"failure":                failure,
+		}).Set(1)
+	}
+
+	if service.CollectedSec != 0 {
+		e.collectedTimestamp.With(labels).Set(float64(service.CollectedSec) + float64(service.CollectedUsec)/1e6)
+	}
+
 	if service.Block != nil {
-		e.blockUsage.With(labels).Set(service.Block.Usage)
-		e.blockTotal.With(labels).Set(service.Block.Total)
-		e.blockPercent.With(labels).Set(service.Block.Percent)
+		e.blockUsage.With(labels).Set(float64(service.Block.Usage))
+		e.blockTotal.With(labels).Set(float64(service.Block.Total))
+		e.blockPercent.With(labels).Set(float64(service.Block.Percent))
 	}
 
 	if service.Inode != nil {
 		e.inodeUsage.With(labels).Set(float64(service.Inode.Usage))
 		e.inodeTotal.With(labels).Set(float64(service.Inode.Total))
-		e.inodePercent.With(labels).Set(service.Inode.Percent)
+		e.inodePercent.With(labels).Set(float64(service.Inode.Percent))
 	}
 
 	if service.Port != nil {
-		e.portResponseTime.With(labels).Set(service.Port.Responsetime)
+		e.portResponseTime.With(labels).Set(float64(service.Port.Responsetime))
+		if service.Port.Certificate != nil {
+			e.certificateValidDays.With(labels).Set(float64(service.Port.Certificate.Valid))
+		}
 	}
 
 	if service.System != nil {
-		e.systemLoadAvg01.With(labels).Set(service.System.Load.Avg01)
-		e.systemLoadAvg05.With(labels).Set(service.System.Load.Avg05)
-		e.systemLoadAvg15.With(labels).Set(service.System.Load.Avg15)
+		e.systemLoadAvg01.With(labels).Set(float64(service.System.Load.Avg01))
+		e.systemLoadAvg05.With(labels).Set(float64(service.System.Load.Avg05))
+		e.systemLoadAvg15.With(labels).Set(float64(service.System.Load.Avg15))
 
-		e.systemCPUUser.With(labels).Set(service.System.CPU.User)
-		e.systemCPUSystem.With(labels).Set(service.System.CPU.System)
-		e.systemCPUWait.With(labels).Set(service.System.CPU.Wait)
+		e.systemCPUUser.With(labels).Set(float64(service.System.CPU.User))
+		e.systemCPUSystem.With(labels).Set(float64(service.System.CPU.System))
+		e.systemCPUWait.With(labels).Set(float64(service.System.CPU.Wait))
 
-		e.systemMemPercent.With(labels).Set(service.System.Memory.Percent)
+		e.systemMemPercent.With(labels).Set(float64(service.System.Memory.Percent))
 		e.systemMemKilobytes.With(labels).Set(float64(service.System.Memory.Kilobyte))
-		e.systemSwapPercent.With(labels).Set(service.System.Swap.Percent)
+		e.systemSwapPercent.With(labels).Set(float64(service.System.Swap.Percent))
 		e.systemSwapKilobytes.With(labels).Set(float64(service.System.Swap.Kilobyte))
 	}
 }
@@ -1,14 +1,22 @@
 package exporter
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/prometheus/client_golang/prometheus"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/ririnto/monit-exporter/internal/breaker"
 	"github.com/ririnto/monit-exporter/internal/config"
+	"github.com/ririnto/monit-exporter/internal/monit"
+	"github.com/ririnto/monit-exporter/internal/monittest"
 	"github.com/sirupsen/logrus"
 )
 
@@ -37,30 +45,15 @@ func TestNewExporter_ValidConfig(t *testing.T) {
 	}
 }
 
-// TestExporter_Collect_Success uses a mock server returning a valid Monit XML response.
+// TestExporter_Collect_Success uses the simulated Monit returning a valid Monit XML response.
 func TestExporter_Collect_Success(t *testing.T) {
 	t.Log("Testing Exporter.Collect with a successful Monit response")
 
-	mockXML := `<?xml version="1.0"?>
-    <monit>
-      <server><version>5.26.0</version></server>
-      <platform/>
-      <service type="0">
-        <name>rootfs</name>
-        <status>0</status>
-        <monitor>1</monitor>
-      </service>
-    </monit>`
-
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		t.Logf("Mock server received request: %s %s", r.Method, r.URL.Path)
-		w.WriteHeader(http.StatusOK)
-		_, _ = fmt.Fprintln(w, mockXML)
-	}))
+	server := monittest.NewServer(monittest.Options{})
 	defer server.Close()
 
 	cfg := &config.Config{
-		MonitScrapeURI: server.URL,
+		MonitScrapeURI: server.URL + "/_status?format=xml&level=full",
 	}
 	exp, err := NewExporter(cfg)
 	if err != nil {
@@ -80,6 +73,9 @@ func TestExporter_Collect_Success(t *testing.T) {
 	if upValue != 1 {
 		t.Errorf("Expected exporter_up=1, got %f", upValue)
 	}
+	if count := testutil.CollectAndCount(exp.status); count != 9 {
+		t.Errorf("Expected a service check for each of the 9 simulated services, got %d", count)
+	}
 }
 
 // TestExporter_Collect_MonitError uses a mock server that returns an HTTP error.
@@ -114,3 +110,335 @@ func TestExporter_Logs(t *testing.T) {
 	logrus.SetLevel(logrus.DebugLevel)
 	t.Log("Testing Exporter logs at DebugLevel (mock scenario)")
 }
+
+// TestExporter_State verifies that fetch outcomes are recorded in the fetch state.
+func TestExporter_State(t *testing.T) {
+	t.Log("Testing Exporter.State after successful and failed fetches")
+
+	healthy := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy {
+			http.Error(w, "some error", http.StatusInternalServerError)
+			return
+		}
+		_, _ = fmt.Fprint(w, `<?xml version="1.0"?><monit><server/><platform/></monit>`)
+	}))
+	defer server.Close()
+
+	exp, err := NewExporter(&config.Config{MonitScrapeURI: server.URL})
+	if err != nil {
+		t.Fatalf("Failed to create Exporter: %v", err)
+	}
+	if state := exp.State(); !state.LastAttempt.IsZero() {
+		t.Errorf("Expected empty state before first fetch, got %+v", state)
+	}
+
+	if err := exp.scrape(context.Background()); err != nil {
+		t.Fatalf("Expected successful scrape, got %v", err)
+	}
+	success := exp.State()
+	if success.LastSuccess.IsZero() || success.LastError != nil {
+		t.Errorf("Expected recorded success, got %+v", success)
+	}
+
+	healthy = false
+	if err := exp.scrape(context.Background()); err == nil {
+		t.Fatal("Expected scrape error, got nil")
+	}
+	failure := exp.State()
+	if failure.LastError == nil {
+		t.Errorf("Expected recorded error, got nil")
+	}
+	if !failure.LastSuccess.Equal(success.LastSuccess) {
+		t.Errorf("Expected LastSuccess to be kept after failure, got %v", failure.LastSuccess)
+	}
+}
+
+// TestExporter_Fetch_Retries verifies that transient failures are retried within the deadline.
+func TestExporter_Fetch_Retries(t *testing.T) {
+	t.Log("Testing Exporter.Fetch retrying a transient failure")
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			http.Error(w, "some error", http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = fmt.Fprint(w, `<?xml version="1.0"?><monit><server/><platform/></monit>`)
+	}))
+	defer server.Close()
+
+	exp, err := NewExporter(&config.Config{
+		MonitScrapeURI:  server.URL,
+		ScrapeTimeout:   time.Second,
+		MaxRetries:      2,
+		RetryMinBackoff: time.Millisecond,
+		RetryMaxBackoff: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create Exporter: %v", err)
+	}
+	if _, err := exp.Fetch(); err != nil {
+		t.Fatalf("Expected the retry to succeed, got %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+	if value := testutil.ToFloat64(exp.retries); value != 1 {
+		t.Errorf("Expected 1 retry, got %f", value)
+	}
+}
+
+// TestExporter_Fetch_Deadline verifies that no retry is started when it cannot finish before the deadline.
+func TestExporter_Fetch_Deadline(t *testing.T) {
+	t.Log("Testing Exporter.Fetch giving up at the scrape deadline")
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		http.Error(w, "some error", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	exp, err := NewExporter(&config.Config{
+		MonitScrapeURI:  server.URL,
+		ScrapeTimeout:   100 * time.Millisecond,
+		MaxRetries:      5,
+		RetryMinBackoff: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create Exporter: %v", err)
+	}
+	start := time.Now()
+	if _, err := exp.Fetch(); err == nil {
+		t.Fatal("Expected a fetch error, got nil")
+	}
+	if requests != 1 || time.Since(start) > time.Second {
+		t.Errorf("Expected a single request without waiting, got %d requests in %v", requests, time.Since(start))
+	}
+}
+
+// TestExporter_Fetch_CircuitBreaker verifies that an open breaker stops contacting Monit.
+func TestExporter_Fetch_CircuitBreaker(t *testing.T) {
+	t.Log("Testing Exporter.Fetch with the circuit breaker")
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		http.Error(w, "some error", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	exp, err := NewExporter(&config.Config{MonitScrapeURI: server.URL, BreakerThreshold: 2, BreakerCooldown: time.Hour})
+	if err != nil {
+		t.Fatalf("Failed to create Exporter: %v", err)
+	}
+	for range 4 {
+		_, err = exp.Fetch()
+	}
+	if !errors.Is(err, breaker.ErrOpen) {
+		t.Errorf("Expected breaker.ErrOpen, got %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected Monit to be contacted twice, got %d requests", requests)
+	}
+	if value := testutil.ToFloat64(exp.breakerState); value != float64(breaker.Open) {
+		t.Errorf("Expected the breaker state to be open, got %f", value)
+	}
+}
+
+// TestExporter_Scrape_GracePeriod verifies that the last good snapshot is served as stale within the grace period.
+func TestExporter_Scrape_GracePeriod(t *testing.T) {
+	t.Log("Testing Exporter.scrape serving a stale snapshot")
+
+	healthy := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy {
+			http.Error(w, "some error", http.StatusInternalServerError)
+			return
+		}
+		_, _ = fmt.Fprint(w, `<?xml version="1.0"?><monit><server/><platform/><service type="3"><name>nginx</name><monitor>1</monitor></service></monit>`)
+	}))
+	defer server.Close()
+
+	exp, err := NewExporter(&config.Config{MonitScrapeURI: server.URL, GracePeriod: time.Minute})
+	if err != nil {
+		t.Fatalf("Failed to create Exporter: %v", err)
+	}
+	if err := exp.scrape(context.Background()); err != nil {
+		t.Fatalf("Expected successful scrape, got %v", err)
+	}
+
+	healthy = false
+	exp.status.Reset()
+	if err := exp.scrape(context.Background()); err == nil {
+		t.Fatal("Expected scrape error, got nil")
+	}
+	if count := testutil.CollectAndCount(exp.status); count != 1 {
+		t.Errorf("Expected the last good snapshot to be served, got %d series", count)
+	}
+	if value := testutil.ToFloat64(exp.snapshotStale); value != 1 {
+		t.Errorf("Expected monit_exporter_snapshot_stale=1, got %f", value)
+	}
+	if value := testutil.ToFloat64(exp.up); value != 0 {
+		t.Errorf("Expected monit_exporter_up=0, got %f", value)
+	}
+
+	exp.lastGoodAt = time.Now().Add(-2 * time.Minute)
+	exp.status.Reset()
+	if err := exp.scrape(context.Background()); err == nil {
+		t.Fatal("Expected scrape error, got nil")
+	}
+	if count := testutil.CollectAndCount(exp.status); count != 0 {
+		t.Errorf("Expected series to be dropped after the grace period, got %d", count)
+	}
+	if value := testutil.ToFloat64(exp.snapshotStale); value != 0 {
+		t.Errorf("Expected monit_exporter_snapshot_stale=0, got %f", value)
+	}
+}
+
+// TestServiceMetricNames verifies that metric names depend on the service details present.
+func TestServiceMetricNames(t *testing.T) {
+	t.Log("Testing ServiceMetricNames for filesystem and process services")
+
+	names, err := ServiceMetricNames(monit.Service{Type: 0, Name: "rootfs", Block: &monit.Block{}})
+	if err != nil {
+		t.Fatalf("ServiceMetricNames failed: %v", err)
+	}
+	want := []string{
+		"monit_exporter_service_check",
+		"monit_service_block_total_bytes",
+		"monit_service_block_usage_bytes",
+		"monit_service_block_usage_percent",
+	}
+	if !slices.Equal(names, want) {
+		t.Errorf("Expected %v, got %v", want, names)
+	}
+
+	names, err = ServiceMetricNames(monit.Service{Type: 3, Name: "nginx"})
+	if err != nil {
+		t.Fatalf("ServiceMetricNames failed: %v", err)
+	}
+	if !slices.Equal(names, []string{"monit_exporter_service_check"}) {
+		t.Errorf("Expected only the service check metric, got %v", names)
+	}
+}
+
+// TestExporter_Descriptors verifies the descriptor list matches the metrics the Exporter describes.
+func TestExporter_Descriptors(t *testing.T) {
+	t.Log("Testing Exporter.Descriptors")
+	exp, err := NewExporter(&config.Config{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	descriptors := exp.Descriptors()
+	ch := make(chan *prometheus.Desc, 64)
+	exp.Describe(ch)
+	close(ch)
+	if len(ch) != len(descriptors) {
+		t.Fatalf("Expected %d descriptors, got %d", len(ch), len(descriptors))
+	}
+	if descriptors[0].Name != "monit_exporter_up" || len(descriptors[0].Labels) != 0 {
+		t.Errorf("Expected unlabelled monit_exporter_up first, got %+v", descriptors[0])
+	}
+	if descriptors[1].Name != "monit_exporter_malformed_services" || len(descriptors[1].Labels) != 0 {
+		t.Errorf("Expected unlabelled monit_exporter_malformed_services second, got %+v", descriptors[1])
+	}
+	if descriptors[2].Name != "monit_exporter_fetch_retries_total" || descriptors[2].Type != prometheus.CounterValue {
+		t.Errorf("Expected the monit_exporter_fetch_retries_total counter third, got %+v", descriptors[2])
+	}
+	if descriptors[0].Type != prometheus.GaugeValue || descriptors[6].Type != prometheus.GaugeValue {
+		t.Errorf("Expected gauges, got %+v and %+v", descriptors[0], descriptors[6])
+	}
+	if !slices.Equal(descriptors[6].Labels, []string{"service_name", "service_type", "service_monitor_status"}) {
+		t.Errorf("Expected per-service labels, got %v", descriptors[6].Labels)
+	}
+	for desc := range ch {
+		if !slices.ContainsFunc(descriptors, func(d Descriptor) bool {
+			return strings.Contains(desc.String(), fmt.Sprintf("fqName: %q", d.Name))
+		}) {
+			t.Errorf("Missing descriptor for %s", desc)
+		}
+	}
+}
+
+// TestExporter_Update_FailureCertificateAndCollected verifies the per-bit failure, certificate and collection metrics.
+func TestExporter_Update_FailureCertificateAndCollected(t *testing.T) {
+	t.Log("Testing failure, certificate and collection time metrics")
+	exp, err := NewExporter(&config.Config{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	exp.update(monit.Monit{Services: []monit.Service{
+		{Type: 4, Name: "api", Status: 0x8 | 0x20, Monitor: 1, CollectedSec: 1700000000, CollectedUsec: 500000,
+			Port: &monit.Port{Certificate: &monit.Certificate{Valid: 12}}},
+		{Type: 4, Name: "plain", Monitor: 1, Port: &monit.Port{}},
+	}})
+
+	labels := prometheus.Labels{"service_name": "api", "service_type": "Remote host", "service_monitor_status": "1"}
+	for _, failure := range []string{"timestamp", "connection"} {
+		withFailure := prometheus.Labels{"failure": failure}
+		for name, value := range labels {
+			withFailure[name] = value
+		}
+		if value := testutil.ToFloat64(exp.failure.With(withFailure)); value != 1 {
+			t.Errorf("Expected failure %s to be 1, got %f", failure, value)
+		}
+	}
+	if count := testutil.CollectAndCount(exp.failure); count != 2 {
+		t.Errorf("Expected 2 failure series, got %d", count)
+	}
+	if value := testutil.ToFloat64(exp.certificateValidDays.With(labels)); value != 12 {
+		t.Errorf("Expected certificate valid days 12, got %f", value)
+	}
+	if count := testutil.CollectAndCount(exp.certificateValidDays); count != 1 {
+		t.Errorf("Expected no certificate series for a plain port, got %d series", count)
+	}
+	if value := testutil.ToFloat64(exp.collectedTimestamp.With(labels)); value != 1700000000.5 {
+		t.Errorf("Expected collected timestamp 1700000000.5, got %f", value)
+	}
+}
+
+// TestExporter_Update_Malformed verifies that skipped services are reported.
+func TestExporter_Update_Malformed(t *testing.T) {
+	t.Log("Testing the malformed services gauge")
+	exp, err := NewExporter(&config.Config{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	parsed, err := monit.ParseMonitStatus([]byte(`<monit><server/><platform/>
+		<service type="3"><name>nginx</name><status>0</status><monitor>1</monitor></service>
+		<service type="3"><name>broken</name><status>??</status></service></monit>`))
+	if err != nil {
+		t.Fatalf("ParseMonitStatus failed: %v", err)
+	}
+	exp.update(parsed)
+	if value := testutil.ToFloat64(exp.malformed); value != 1 {
+		t.Errorf("Expected 1 malformed service, got %f", value)
+	}
+	if count := testutil.CollectAndCount(exp.status); count != 1 {
+		t.Errorf("Expected the well-formed service to be exported, got %d series", count)
+	}
+}
+
+// FuzzExporter_Update checks that any snapshot the parser accepts can be exported without panicking.
+func FuzzExporter_Update(f *testing.F) {
+	f.Add([]byte(`<monit><service type="0"><name>rootfs</name><block><percent>42</percent></block></service></monit>`))
+	f.Add([]byte(`<monit><service type="99"><name></name><status>-1</status><port><certificate><valid>1</valid></certificate></port></service></monit>`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		parsed, err := monit.ParseMonitStatus(data)
+		if err != nil {
+			return
+		}
+		exp, err := NewExporter(&config.Config{})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		exp.update(parsed)
+	})
+}
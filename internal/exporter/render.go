@@ -0,0 +1,77 @@
+package exporter
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+	"github.com/sirupsen/logrus"
+)
+
+// WriteText collects the exporter once through a private registry and writes
+// the result to w in the Prometheus text exposition format.
+func (e *Exporter) WriteText(w io.Writer) error {
+	registry := prometheus.NewRegistry()
+	if err := registry.Register(e); err != nil {
+		return fmt.Errorf("unable to register exporter: %w", err)
+	}
+	families, err := registry.Gather()
+	if err != nil {
+		return fmt.Errorf("unable to gather metrics: %w", err)
+	}
+
+	encoder := expfmt.NewEncoder(w, expfmt.NewFormat(expfmt.TypeTextPlain))
+	for family := range slices.Values(families) {
+		if err := encoder.Encode(family); err != nil {
+			return fmt.Errorf("unable to encode metric family %s: %w", family.GetName(), err)
+		}
+	}
+	logrus.Debugf("Exporter.WriteText: wrote %d metric families", len(families))
+	return nil
+}
+
+// WriteTextFile atomically replaces path with the text exposition of the exporter.
+// The metrics are written to a hidden temporary file in the same directory, which is
+// then renamed over path, so readers such as the node_exporter textfile collector
+// never observe a partially written file.
+func (e *Exporter) WriteTextFile(path string) (err error) {
+	dir, name := filepath.Split(path)
+	if dir == "" {
+		dir = "."
+	}
+	tmp, err := os.CreateTemp(dir, "."+name+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("unable to create temporary file: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tmp.Close()
+			if rerr := os.Remove(tmp.Name()); rerr != nil && !errors.Is(rerr, os.ErrNotExist) {
+				logrus.Warnf("Exporter.WriteTextFile: failed to remove temporary file: %v", rerr)
+			}
+		}
+	}()
+
+	if err = e.WriteText(tmp); err != nil {
+		return err
+	}
+	if err = tmp.Chmod(0o644); err != nil {
+		return fmt.Errorf("unable to set file mode: %w", err)
+	}
+	if err = tmp.Sync(); err != nil {
+		return fmt.Errorf("unable to sync temporary file: %w", err)
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("unable to close temporary file: %w", err)
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("unable to rename temporary file: %w", err)
+	}
+	logrus.Debugf("Exporter.WriteTextFile: wrote metrics to %s", path)
+	return nil
+}
@@ -0,0 +1,140 @@
+package exporter
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ririnto/monit-exporter/internal/config"
+)
+
+// TestExporter_WriteText verifies the text exposition rendered from a local Monit XML file.
+func TestExporter_WriteText(t *testing.T) {
+	t.Log("Testing Exporter.WriteText with a file:// scrape URI")
+
+	mockXML := `<?xml version="1.0"?>
+    <monit>
+      <server><version>5.26.0</version></server>
+      <platform/>
+      <service type="0">
+        <name>rootfs</name>
+        <status>0</status>
+        <monitor>1</monitor>
+        <block><percent>42.5</percent><usage>1024</usage><total>4096</total></block>
+      </service>
+    </monit>`
+	path := filepath.Join(t.TempDir(), "status.xml")
+	if err := os.WriteFile(path, []byte(mockXML), 0o600); err != nil {
+		t.Fatalf("Failed to write fixture: %v", err)
+	}
+
+	exp, err := NewExporter(&config.Config{MonitScrapeURI: "file://" + path})
+	if err != nil {
+		t.Fatalf("Failed to create Exporter: %v", err)
+	}
+	buf := new(bytes.Buffer)
+	if err := exp.WriteText(buf); err != nil {
+		t.Fatalf("WriteText failed: %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{
+		"# TYPE monit_exporter_up gauge",
+		"monit_exporter_up 1",
+		`monit_service_block_usage_percent{service_monitor_status="1",service_name="rootfs",service_type="Filesystem"} 42.5`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+// TestExporter_WriteTextFile verifies that the metrics file is replaced without leftovers.
+func TestExporter_WriteTextFile(t *testing.T) {
+	t.Log("Testing Exporter.WriteTextFile in a temporary directory")
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "monit.prom")
+	if err := os.WriteFile(path, []byte("stale\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write stale file: %v", err)
+	}
+
+	exp, err := NewExporter(&config.Config{MonitScrapeURI: "file://" + filepath.Join(dir, "missing.xml")})
+	if err != nil {
+		t.Fatalf("Failed to create Exporter: %v", err)
+	}
+	if err := exp.WriteTextFile(path); err != nil {
+		t.Fatalf("WriteTextFile failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read metrics file: %v", err)
+	}
+	if !strings.Contains(string(data), "monit_exporter_up 0") {
+		t.Errorf("Expected rewritten metrics file, got:\n%s", data)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Failed to stat metrics file: %v", err)
+	}
+	if info.Mode().Perm() != 0o644 {
+		t.Errorf("Expected file mode 0644, got %v", info.Mode().Perm())
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Failed to read directory: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("Expected only the metrics file to remain, got %d entries", len(entries))
+	}
+}
+
+// update rewrites the golden files instead of comparing against them.
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// TestExporter_WriteText_Golden renders the captured Monit XML of each supported version
+// and compares the exposition with the golden .prom file in testdata.
+func TestExporter_WriteText_Golden(t *testing.T) {
+	fixtures, err := filepath.Glob(filepath.Join("..", "monit", "testdata", "*.xml"))
+	if err != nil || len(fixtures) == 0 {
+		t.Fatalf("Expected XML fixtures in ../monit/testdata, got %v (err=%v)", fixtures, err)
+	}
+	for _, fixture := range fixtures {
+		name := strings.TrimSuffix(filepath.Base(fixture), ".xml")
+		t.Run(name, func(t *testing.T) {
+			path, err := filepath.Abs(fixture)
+			if err != nil {
+				t.Fatalf("Failed to resolve fixture: %v", err)
+			}
+			exp, err := NewExporter(&config.Config{MonitScrapeURI: "file://" + filepath.ToSlash(path)})
+			if err != nil {
+				t.Fatalf("Failed to create Exporter: %v", err)
+			}
+			buf := new(bytes.Buffer)
+			if err := exp.WriteText(buf); err != nil {
+				t.Fatalf("WriteText failed: %v", err)
+			}
+
+			golden := filepath.Join("testdata", name+".prom")
+			if *update {
+				if err := os.MkdirAll("testdata", 0o755); err != nil {
+					t.Fatalf("Failed to create testdata: %v", err)
+				}
+				if err := os.WriteFile(golden, buf.Bytes(), 0o644); err != nil {
+					t.Fatalf("Failed to update golden file: %v", err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("Failed to read golden file (run with -update to create it): %v", err)
+			}
+			if !bytes.Equal(buf.Bytes(), want) {
+				t.Errorf("Exposition differs from %s (run with -update if the change is intended):\n%s", golden, buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package grafana
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/ririnto/monit-exporter/internal/exporter"
+)
+
+// Dashboard is the subset of the Grafana dashboard JSON model the exporter generates.
+type Dashboard struct {
+	UID           string     `json:"uid,omitempty"`
+	Title         string     `json:"title"`
+	Tags          []string   `json:"tags"`
+	Timezone      string     `json:"timezone"`
+	SchemaVersion int        `json:"schemaVersion"`
+	Refresh       string     `json:"refresh"`
+	Time          TimeRange  `json:"time"`
+	Templating    Templating `json:"templating"`
+	Panels        []Panel    `json:"panels"`
+}
+
+// TimeRange is the default time range of a dashboard.
+type TimeRange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Templating holds the dashboard variables.
+type Templating struct {
+	List []Variable `json:"list"`
+}
+
+// Variable is a dashboard template variable.
+type Variable struct {
+	Name       string      `json:"name"`
+	Label      string      `json:"label"`
+	Type       string      `json:"type"`
+	Query      string      `json:"query"`
+	Definition string      `json:"definition,omitempty"`
+	Datasource *Datasource `json:"datasource,omitempty"`
+	Refresh    int         `json:"refresh,omitempty"`
+	Multi      bool        `json:"multi"`
+	IncludeAll bool        `json:"includeAll"`
+	Sort       int         `json:"sort,omitempty"`
+}
+
+// Datasource references the Prometheus data source selected by the datasource variable.
+type Datasource struct {
+	Type string `json:"type"`
+	UID  string `json:"uid"`
+}
+
+// Panel is a dashboard panel or row.
+type Panel struct {
+	ID          int          `json:"id"`
+	Type        string       `json:"type"`
+	Title       string       `json:"title"`
+	Description string       `json:"description,omitempty"`
+	GridPos     GridPos      `json:"gridPos"`
+	Datasource  *Datasource  `json:"datasource,omitempty"`
+	Targets     []Target     `json:"targets,omitempty"`
+	FieldConfig *FieldConfig `json:"fieldConfig,omitempty"`
+	Collapsed   bool         `json:"collapsed,omitempty"`
+}
+
+// GridPos positions a panel on the 24 column dashboard grid.
+type GridPos struct {
+	H int `json:"h"`
+	W int `json:"w"`
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// Target is a Prometheus query of a panel.
+type Target struct {
+	RefID        string `json:"refId"`
+	Expr         string `json:"expr"`
+	LegendFormat string `json:"legendFormat,omitempty"`
+}
+
+// FieldConfig holds the field defaults of a panel.
+type FieldConfig struct {
+	Defaults  FieldDefaults `json:"defaults"`
+	Overrides []any         `json:"overrides"`
+}
+
+// FieldDefaults holds the default unit of a panel's fields.
+type FieldDefaults struct {
+	Unit string `json:"unit"`
+}
+
+// Options configures the generated dashboard.
+type Options struct {
+	Title string
+	UID   string
+}
+
+// datasource is the data source every panel and variable queries.
+var datasource = &Datasource{Type: "prometheus", UID: "${datasource}"}
+
+// unit returns the Grafana unit for a metric based on its name suffix.
+func unit(name string) string {
+	switch {
+	case strings.HasSuffix(name, "_kilobytes"):
+		return "kbytes"
+	case strings.HasSuffix(name, "_bytes"):
+		return "bytes"
+	case strings.HasSuffix(name, "_seconds"):
+		return "s"
+	case strings.HasSuffix(name, "_percent"):
+		return "percent"
+	case strings.HasSuffix(name, "_days"):
+		return "d"
+	default:
+		return "short"
+	}
+}
+
+// selector returns the label matchers a panel query applies for the dashboard variables.
+func selector(descriptor exporter.Descriptor) string {
+	if slices.Contains(descriptor.Labels, "service_name") {
+		return `{instance=~"$instance",service_name=~"$service"}`
+	}
+	return `{instance=~"$instance"}`
+}
+
+// query returns the panel query for a descriptor and the unit of its result. Timestamps
+// are shown as their age in seconds and counters as their increase, over the dashboard
+// range for stat panels and per rate interval for time series.
+func query(descriptor exporter.Descriptor, panelType string) (string, string) {
+	series := descriptor.Name + selector(descriptor)
+	switch {
+	case strings.HasSuffix(descriptor.Name, "_timestamp_seconds"):
+		return "time() - " + series, "s"
+	case descriptor.Type == prometheus.CounterValue && panelType == "stat":
+		return "increase(" + series + "[$__range])", unit(descriptor.Name)
+	case descriptor.Type == prometheus.CounterValue:
+		return "increase(" + series + "[$__rate_interval])", unit(descriptor.Name)
+	default:
+		return series, unit(descriptor.Name)
+	}
+}
+
+// legend returns the legend format showing the instance and every descriptor label.
+func legend(descriptor exporter.Descriptor) string {
+	parts := []string{"{{instance}}"}
+	for label := range slices.Values(descriptor.Labels) {
+		if label == "service_type" || label == "service_monitor_status" {
+			continue
+		}
+		parts = append(parts, "{{"+label+"}}")
+	}
+	return strings.Join(parts, " ")
+}
+
+// variables returns the datasource, instance and service variables.
+func variables() []Variable {
+	instanceQuery := "label_values(monit_exporter_up, instance)"
+	serviceQuery := `label_values(monit_exporter_service_check{instance=~"$instance"}, service_name)`
+	return []Variable{
+		{Name: "datasource", Label: "Data source", Type: "datasource", Query: "prometheus"},
+		{Name: "instance", Label: "Instance", Type: "query", Query: instanceQuery, Definition: instanceQuery,
+			Datasource: datasource, Refresh: 2, Multi: true, IncludeAll: true, Sort: 1},
+		{Name: "service", Label: "Service", Type: "query", Query: serviceQuery, Definition: serviceQuery,
+			Datasource: datasource, Refresh: 2, Multi: true, IncludeAll: true, Sort: 1},
+	}
+}
+
+// New builds a dashboard with a panel for every descriptor: a stat panel per
+// exporter-wide metric and a time series panel per service metric.
+func New(descriptors []exporter.Descriptor, opts Options) Dashboard {
+	var exporterMetrics, serviceMetrics []exporter.Descriptor
+	for descriptor := range slices.Values(descriptors) {
+		if len(descriptor.Labels) == 0 {
+			exporterMetrics = append(exporterMetrics, descriptor)
+		} else {
+			serviceMetrics = append(serviceMetrics, descriptor)
+		}
+	}
+
+	var panels []Panel
+	y := 0
+	addRow := func(title string, metrics []exporter.Descriptor, panelType string, w, h int) {
+		if len(metrics) == 0 {
+			return
+		}
+		panels = append(panels, Panel{ID: len(panels) + 1, Type: "row", Title: title, GridPos: GridPos{H: 1, W: 24, Y: y}})
+		y++
+		for i, descriptor := range metrics {
+			x := (i * w) % 24
+			if i > 0 && x == 0 {
+				y += h
+			}
+			expr, exprUnit := query(descriptor, panelType)
+			panels = append(panels, Panel{
+				ID:          len(panels) + 1,
+				Type:        panelType,
+				Title:       descriptor.Name,
+				Description: descriptor.Help,
+				GridPos:     GridPos{H: h, W: w, X: x, Y: y},
+				Datasource:  datasource,
+				Targets: []Target{{
+					RefID:        "A",
+					Expr:         expr,
+					LegendFormat: legend(descriptor),
+				}},
+				FieldConfig: &FieldConfig{Defaults: FieldDefaults{Unit: exprUnit}, Overrides: []any{}},
+			})
+		}
+		y += h
+	}
+	addRow("Exporter", exporterMetrics, "stat", 6, 4)
+	addRow("Services", serviceMetrics, "timeseries", 12, 8)
+
+	return Dashboard{
+		UID:           opts.UID,
+		Title:         opts.Title,
+		Tags:          []string{"monit", "monit-exporter"},
+		Timezone:      "browser",
+		SchemaVersion: 39,
+		Refresh:       "30s",
+		Time:          TimeRange{From: "now-6h", To: "now"},
+		Templating:    Templating{List: variables()},
+		Panels:        panels,
+	}
+}
+
+// Metrics returns the metric names queried by the dashboard's panels.
+func (d Dashboard) Metrics() []string {
+	var names []string
+	for panel := range slices.Values(d.Panels) {
+		for target := range slices.Values(panel.Targets) {
+			series, _, _ := strings.Cut(target.Expr, "{")
+			names = append(names, series[strings.LastIndexAny(series, "( ")+1:])
+		}
+	}
+	return names
+}
@@ -0,0 +1,109 @@
+package grafana
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/ririnto/monit-exporter/internal/config"
+	"github.com/ririnto/monit-exporter/internal/exporter"
+)
+
+// TestNew verifies that every descriptor gets exactly one panel querying it.
+func TestNew(t *testing.T) {
+	t.Log("Testing New with the Exporter descriptors")
+	exp, err := exporter.NewExporter(&config.Config{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	descriptors := exp.Descriptors()
+	dashboard := New(descriptors, Options{Title: "Monit", UID: "monit"})
+
+	metrics := dashboard.Metrics()
+	if len(metrics) != len(descriptors) {
+		t.Fatalf("Expected %d queried metrics, got %d", len(descriptors), len(metrics))
+	}
+	for _, descriptor := range descriptors {
+		if !slices.Contains(metrics, descriptor.Name) {
+			t.Errorf("Expected a panel for %s", descriptor.Name)
+		}
+	}
+
+	var names []string
+	for _, variable := range dashboard.Templating.List {
+		names = append(names, variable.Name)
+	}
+	if !slices.Equal(names, []string{"datasource", "instance", "service"}) {
+		t.Errorf("Expected datasource, instance and service variables, got %v", names)
+	}
+	if dashboard.Title != "Monit" || dashboard.UID != "monit" {
+		t.Errorf("Expected options to be applied, got %q/%q", dashboard.Title, dashboard.UID)
+	}
+}
+
+// TestNew_Panels verifies panel types, queries, units and layout.
+func TestNew_Panels(t *testing.T) {
+	t.Log("Testing New panel generation")
+	dashboard := New([]exporter.Descriptor{
+		{Name: "monit_exporter_up", Help: "Up."},
+		{Name: "monit_service_failure", Help: "Failure.", Labels: []string{"service_name", "service_type", "service_monitor_status", "failure"}},
+		{Name: "monit_service_block_usage_percent", Help: "Block.", Labels: []string{"service_name", "service_type", "service_monitor_status"}},
+		{Name: "monit_service_collected_timestamp_seconds", Help: "Collected.", Labels: []string{"service_name", "service_type", "service_monitor_status"}},
+	}, Options{Title: "Monit"})
+
+	tests := []struct {
+		id     int
+		typ    string
+		expr   string
+		legend string
+		unit   string
+		pos    GridPos
+	}{
+		{2, "stat", `monit_exporter_up{instance=~"$instance"}`, "{{instance}}", "short", GridPos{H: 4, W: 6, X: 0, Y: 1}},
+		{4, "timeseries", `monit_service_failure{instance=~"$instance",service_name=~"$service"}`,
+			"{{instance}} {{service_name}} {{failure}}", "short", GridPos{H: 8, W: 12, X: 0, Y: 6}},
+		{5, "timeseries", `monit_service_block_usage_percent{instance=~"$instance",service_name=~"$service"}`,
+			"{{instance}} {{service_name}}", "percent", GridPos{H: 8, W: 12, X: 12, Y: 6}},
+		{6, "timeseries", `time() - monit_service_collected_timestamp_seconds{instance=~"$instance",service_name=~"$service"}`,
+			"{{instance}} {{service_name}}", "s", GridPos{H: 8, W: 12, X: 0, Y: 14}},
+	}
+	if len(dashboard.Panels) != 6 {
+		t.Fatalf("Expected 2 rows and 4 panels, got %d panels", len(dashboard.Panels))
+	}
+	for _, tt := range tests {
+		panel := dashboard.Panels[tt.id-1]
+		if panel.ID != tt.id || panel.Type != tt.typ {
+			t.Errorf("Expected panel %d of type %s, got %d of type %s", tt.id, tt.typ, panel.ID, panel.Type)
+		}
+		if panel.Targets[0].Expr != tt.expr || panel.Targets[0].LegendFormat != tt.legend {
+			t.Errorf("Expected query %s with legend %s, got %+v", tt.expr, tt.legend, panel.Targets[0])
+		}
+		if panel.FieldConfig.Defaults.Unit != tt.unit {
+			t.Errorf("Expected unit %s for %s, got %s", tt.unit, panel.Title, panel.FieldConfig.Defaults.Unit)
+		}
+		if panel.GridPos != tt.pos {
+			t.Errorf("Expected %s at %+v, got %+v", panel.Title, tt.pos, panel.GridPos)
+		}
+	}
+}
+
+// TestQuery_Counter verifies that counters are queried as their increase.
+func TestQuery_Counter(t *testing.T) {
+	t.Log("Testing counter queries")
+	descriptor := exporter.Descriptor{Name: "monit_exporter_fetch_retries_total", Type: prometheus.CounterValue}
+
+	tests := map[string]string{
+		"stat":       `increase(monit_exporter_fetch_retries_total{instance=~"$instance"}[$__range])`,
+		"timeseries": `increase(monit_exporter_fetch_retries_total{instance=~"$instance"}[$__rate_interval])`,
+	}
+	for panelType, want := range tests {
+		if expr, unit := query(descriptor, panelType); expr != want || unit != "short" {
+			t.Errorf("Expected %s with unit short for a %s panel, got %s with unit %s", want, panelType, expr, unit)
+		}
+	}
+
+	dashboard := New([]exporter.Descriptor{descriptor}, Options{})
+	if metrics := dashboard.Metrics(); !slices.Equal(metrics, []string{descriptor.Name}) {
+		t.Errorf("Expected the counter to be reported as queried, got %v", metrics)
+	}
+}
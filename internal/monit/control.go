@@ -0,0 +1,129 @@
+package monit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ririnto/monit-exporter/internal/config"
+	"github.com/sirupsen/logrus"
+)
+
+// securityTokenName is the cookie and form field Monit uses to protect against CSRF.
+const securityTokenName = "securitytoken"
+
+// Actions lists the service actions accepted by the Monit httpd.
+var Actions = []string{"start", "stop", "restart", "monitor", "unmonitor"}
+
+var (
+	// ErrInvalidAction is returned when an action is not one of Actions.
+	ErrInvalidAction = errors.New("invalid action")
+	// ErrInvalidService is returned when the service name is empty.
+	ErrInvalidService = errors.New("invalid service name")
+)
+
+// controlBaseURL derives the Monit httpd base URL from the status scrape URI.
+// A unix:// URI yields http://unix/, which newHTTPClient dials over the socket.
+func controlBaseURL(scrapeURI string) (*url.URL, error) {
+	u, err := url.Parse(scrapeURI)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse Monit URI: %w", err)
+	}
+	base := requestURL(u)
+	base.Path = strings.TrimSuffix(base.Path, "_status")
+	if !strings.HasSuffix(base.Path, "/") {
+		base.Path += "/"
+	}
+	base.RawPath = ""
+	base.RawQuery = ""
+	base.Fragment = ""
+	return base, nil
+}
+
+// ControlService asks the Monit httpd to perform action on the named service.
+// It first requests the service page to obtain Monit's CSRF security token cookie
+// and then submits the action form with that token.
+func ControlService(cfg *config.Config, service, action string) error {
+	logrus.Debugf("ControlService: service=%s, action=%s, MonitScrapeURI=%s", service, action, cfg.MonitScrapeURI)
+
+	if service == "" {
+		return ErrInvalidService
+	}
+	if !slices.Contains(Actions, action) {
+		return fmt.Errorf("%w: %s", ErrInvalidAction, action)
+	}
+
+	base, err := controlBaseURL(cfg.MonitScrapeURI)
+	if err != nil {
+		logrus.Errorf("ControlService: %v", err)
+		return err
+	}
+	serviceURL := base.JoinPath(service)
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return fmt.Errorf("unable to create cookie jar: %w", err)
+	}
+	client := newHTTPClient(cfg)
+	client.Jar = jar
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tokenReq, err := http.NewRequestWithContext(ctx, http.MethodGet, serviceURL.String(), nil)
+	if err != nil {
+		return fmt.Errorf("unable to create request: %w", err)
+	}
+	if err := authorize(tokenReq, cfg); err != nil {
+		return err
+	}
+	if err := doControlRequest(client, tokenReq); err != nil {
+		logrus.Errorf("ControlService: failed to load service page: %v", err)
+		return err
+	}
+
+	form := url.Values{"action": {action}}
+	for _, cookie := range jar.Cookies(serviceURL) {
+		if cookie.Name == securityTokenName {
+			form.Set(securityTokenName, cookie.Value)
+		}
+	}
+
+	actionReq, err := http.NewRequestWithContext(ctx, http.MethodPost, serviceURL.String(), strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("unable to create request: %w", err)
+	}
+	if err := authorize(actionReq, cfg); err != nil {
+		return err
+	}
+	actionReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if err := doControlRequest(client, actionReq); err != nil {
+		logrus.Errorf("ControlService: failed to %s service %s: %v", action, service, err)
+		return err
+	}
+	logrus.Debugf("ControlService: Monit accepted action=%s for service=%s", action, service)
+	return nil
+}
+
+// doControlRequest sends req and returns an error unless Monit answers with a 2xx status.
+func doControlRequest(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to reach Monit: %w", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			logrus.Warnf("doControlRequest: failed to close response body: %v", cerr)
+		}
+	}()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("monit returned non-2xx status code: %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package monit
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ririnto/monit-exporter/internal/config"
+)
+
+// newMockControlServer emulates the Monit httpd CSRF token handling for service actions.
+func newMockControlServer(performed *[]string) *httptest.Server {
+	return httptest.NewServer(mockControlHandler(performed))
+}
+
+// mockControlHandler records the service actions accepted with a valid CSRF token.
+func mockControlHandler(performed *[]string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "admin" || pass != "secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			http.SetCookie(w, &http.Cookie{Name: "securitytoken", Value: "token-123", Path: "/"})
+			w.WriteHeader(http.StatusOK)
+		case http.MethodPost:
+			cookie, err := r.Cookie("securitytoken")
+			if err != nil || cookie.Value != r.FormValue("securitytoken") {
+				http.Error(w, "invalid security token", http.StatusForbidden)
+				return
+			}
+			*performed = append(*performed, r.URL.Path+":"+r.FormValue("action"))
+			w.WriteHeader(http.StatusOK)
+		}
+	})
+}
+
+// TestControlService_Success verifies that an action is posted with the CSRF token.
+func TestControlService_Success(t *testing.T) {
+	t.Log("Testing ControlService against a mock Monit httpd")
+
+	var performed []string
+	server := newMockControlServer(&performed)
+	defer server.Close()
+
+	cfg := &config.Config{
+		MonitScrapeURI: server.URL + "/_status?format=xml&level=full",
+		MonitUser:      "admin",
+		MonitPassword:  "secret",
+	}
+	if err := ControlService(cfg, "nginx", "restart"); err != nil {
+		t.Fatalf("ControlService returned error: %v", err)
+	}
+	if len(performed) != 1 || performed[0] != "/nginx:restart" {
+		t.Errorf("Expected [/nginx:restart], got %v", performed)
+	}
+}
+
+// TestControlService_UnixSocket verifies that actions are sent over the Unix socket of a unix:// URI.
+func TestControlService_UnixSocket(t *testing.T) {
+	t.Log("Testing ControlService over a Unix socket")
+
+	var performed []string
+	socket := newUnixServer(t, mockControlHandler(&performed))
+
+	cfg := &config.Config{MonitScrapeURI: "unix://" + socket, MonitUser: "admin", MonitPassword: "secret"}
+	if err := ControlService(cfg, "nginx", "restart"); err != nil {
+		t.Fatalf("ControlService returned error: %v", err)
+	}
+	if len(performed) != 1 || performed[0] != "/nginx:restart" {
+		t.Errorf("Expected [/nginx:restart], got %v", performed)
+	}
+}
+
+// TestControlService_InvalidAction verifies that unknown actions are rejected locally.
+func TestControlService_InvalidAction(t *testing.T) {
+	t.Log("Testing ControlService with an unsupported action")
+
+	err := ControlService(&config.Config{MonitScrapeURI: "http://localhost:2812/_status"}, "nginx", "reboot")
+	if !errors.Is(err, ErrInvalidAction) {
+		t.Fatalf("Expected ErrInvalidAction, got %v", err)
+	}
+}
+
+// TestControlService_Unauthorized verifies that Monit authentication failures are reported.
+func TestControlService_Unauthorized(t *testing.T) {
+	t.Log("Testing ControlService with wrong Monit credentials")
+
+	var performed []string
+	server := newMockControlServer(&performed)
+	defer server.Close()
+
+	cfg := &config.Config{MonitScrapeURI: server.URL + "/_status", MonitUser: "admin", MonitPassword: "wrong"}
+	if err := ControlService(cfg, "nginx", "stop"); err == nil {
+		t.Fatal("Expected an error for rejected credentials, got nil")
+	}
+	if len(performed) != 0 {
+		t.Errorf("Expected no action to be performed, got %v", performed)
+	}
+}
+
+// TestControlBaseURL verifies derivation of the Monit httpd base URL.
+func TestControlBaseURL(t *testing.T) {
+	t.Log("Testing controlBaseURL with and without a path prefix")
+
+	tests := map[string]string{
+		"http://localhost:2812/_status?format=xml&level=full": "http://localhost:2812/",
+		"https://proxy.example.com/monit/_status?format=xml":  "https://proxy.example.com/monit/",
+		"http://localhost:2812":                               "http://localhost:2812/",
+		"unix:///var/run/monit.sock?format=xml&level=full":    "http://unix/",
+	}
+	for in, want := range tests {
+		got, err := controlBaseURL(in)
+		if err != nil {
+			t.Fatalf("controlBaseURL(%q) returned error: %v", in, err)
+		}
+		if got.String() != want {
+			t.Errorf("controlBaseURL(%q) = %q, want %q", in, got.String(), want)
+		}
+	}
+}
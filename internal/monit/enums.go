@@ -0,0 +1,166 @@
+package monit
+
+import (
+	"encoding/json"
+	"slices"
+	"strconv"
+)
+
+// serviceTypeNames maps Monit service type integers to descriptive strings.
+var serviceTypeNames = map[Int]string{
+	0: "Filesystem",
+	1: "Directory",
+	2: "File",
+	3: "Process",
+	4: "Remote host",
+	5: "System",
+	6: "Fifo",
+	7: "Program",
+	8: "Network",
+}
+
+// Failure describes a single event bit of the Monit <status> bitmask.
+type Failure struct {
+	Bit  Int
+	Name string
+}
+
+// Failures lists the Monit event bits that may be set in a service <status>, in bit order.
+var Failures = []Failure{
+	{0x1, "checksum"},
+	{0x2, "resource"},
+	{0x4, "timeout"},
+	{0x8, "timestamp"},
+	{0x10, "size"},
+	{0x20, "connection"},
+	{0x40, "permission"},
+	{0x80, "uid"},
+	{0x100, "gid"},
+	{0x200, "nonexist"},
+	{0x400, "invalid"},
+	{0x800, "data"},
+	{0x1000, "exec"},
+	{0x2000, "fsflags"},
+	{0x4000, "icmp"},
+	{0x8000, "content"},
+	{0x10000, "instance"},
+	{0x20000, "action"},
+	{0x40000, "pid"},
+	{0x80000, "ppid"},
+	{0x100000, "heartbeat"},
+	{0x200000, "status"},
+	{0x400000, "uptime"},
+	{0x800000, "link"},
+	{0x1000000, "speed"},
+	{0x2000000, "saturation"},
+	{0x4000000, "bytein"},
+	{0x8000000, "byteout"},
+	{0x10000000, "packetin"},
+	{0x20000000, "packetout"},
+	{0x40000000, "exist"},
+}
+
+// monitorStateNames maps the <monitor> value to a descriptive string.
+var monitorStateNames = map[Int]string{
+	0: "not monitored",
+	1: "monitored",
+	2: "initializing",
+	4: "waiting",
+}
+
+// monitorModeNames maps the <monitormode> value to a descriptive string.
+var monitorModeNames = map[Int]string{
+	0: "active",
+	1: "passive",
+	2: "manual",
+}
+
+// onRebootNames maps the <onreboot> value to a descriptive string.
+var onRebootNames = map[Int]string{
+	0: "start",
+	1: "nostart",
+	2: "laststate",
+}
+
+// pendingActionNames maps the <pendingaction> value to a descriptive string.
+var pendingActionNames = map[Int]string{
+	0: "none",
+	1: "alert",
+	2: "restart",
+	3: "stop",
+	4: "exec",
+	5: "unmonitor",
+	6: "start",
+	7: "monitor",
+}
+
+// lookupName returns the name for v in names, or "unknown(v)" if there is none.
+func lookupName(names map[Int]string, v Int) string {
+	if name, ok := names[v]; ok {
+		return name
+	}
+	return "unknown(" + strconv.FormatInt(int64(v), 10) + ")"
+}
+
+// ServiceTypeName returns the descriptive name of a Monit service type and whether it is known.
+func ServiceTypeName(t Int) (string, bool) {
+	name, ok := serviceTypeNames[t]
+	return name, ok
+}
+
+// StatusFailures decodes a Monit <status> bitmask into the names of the failing checks.
+func StatusFailures(status Int) []string {
+	failures := []string{}
+	for failure := range slices.Values(Failures) {
+		if status&failure.Bit != 0 {
+			failures = append(failures, failure.Name)
+		}
+	}
+	return failures
+}
+
+// MonitorStateName returns the descriptive name of a <monitor> value.
+func MonitorStateName(monitor Int) string {
+	return lookupName(monitorStateNames, monitor)
+}
+
+// MonitorModeName returns the descriptive name of a <monitormode> value.
+func MonitorModeName(mode Int) string {
+	return lookupName(monitorModeNames, mode)
+}
+
+// OnRebootName returns the descriptive name of an <onreboot> value.
+func OnRebootName(onReboot Int) string {
+	return lookupName(onRebootNames, onReboot)
+}
+
+// PendingActionName returns the descriptive name of a <pendingaction> value.
+func PendingActionName(action Int) string {
+	return lookupName(pendingActionNames, action)
+}
+
+// MarshalJSON encodes the service with its raw fields plus the decoded enum names.
+func (s Service) MarshalJSON() ([]byte, error) {
+	type service Service
+	typeName, ok := ServiceTypeName(s.Type)
+	if !ok {
+		typeName = lookupName(nil, s.Type)
+	}
+	return json.Marshal(struct {
+		service
+		TypeName          string   `json:"type_name"`
+		StatusFailures    []string `json:"status_failures"`
+		MonitorName       string   `json:"monitor_name"`
+		MonitorModeName   string   `json:"monitormode_name"`
+		OnRebootName      string   `json:"onreboot_name"`
+		PendingActionName string   `json:"pendingaction_name"`
+	}{
+		service:           service(s),
+		TypeName:          typeName,
+		StatusFailures:    StatusFailures(s.Status),
+		MonitorName:       MonitorStateName(s.Monitor),
+		MonitorModeName:   MonitorModeName(s.MonitorMode),
+		OnRebootName:      OnRebootName(s.OnReboot),
+		PendingActionName: PendingActionName(s.PendingAction),
+	})
+}
@@ -0,0 +1,66 @@
+package monit
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+// TestStatusFailures verifies decoding of the Monit status bitmask.
+func TestStatusFailures(t *testing.T) {
+	t.Log("Testing StatusFailures with combined event bits")
+
+	got := StatusFailures(0x20 | 0x200)
+	want := []string{"connection", "nonexist"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if got := StatusFailures(0); len(got) != 0 {
+		t.Errorf("Expected no failures for status 0, got %v", got)
+	}
+}
+
+// TestEnumNames verifies decoding of the single-valued Monit enums.
+func TestEnumNames(t *testing.T) {
+	t.Log("Testing enum name lookups including unknown values")
+
+	if name, ok := ServiceTypeName(4); !ok || name != "Remote host" {
+		t.Errorf("Expected 'Remote host', got '%s' (ok=%t)", name, ok)
+	}
+	if _, ok := ServiceTypeName(42); ok {
+		t.Errorf("Expected unknown service type 42 to be reported as unknown")
+	}
+	if got := MonitorStateName(0); got != "not monitored" {
+		t.Errorf("Expected 'not monitored', got '%s'", got)
+	}
+	if got := PendingActionName(2); got != "restart" {
+		t.Errorf("Expected 'restart', got '%s'", got)
+	}
+	if got := MonitorModeName(9); got != "unknown(9)" {
+		t.Errorf("Expected 'unknown(9)', got '%s'", got)
+	}
+}
+
+// TestService_MarshalJSON verifies that decoded enum names are added to the JSON output.
+func TestService_MarshalJSON(t *testing.T) {
+	t.Log("Testing Service.MarshalJSON output fields")
+
+	data, err := json.Marshal(Service{Type: 7, Name: "backup", Status: 0x2, Program: &Program{Output: "ok"}})
+	if err != nil {
+		t.Fatalf("MarshalJSON failed: %v", err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if decoded["name"] != "backup" || decoded["type_name"] != "Program" {
+		t.Errorf("Expected name 'backup' and type_name 'Program', got %v", decoded)
+	}
+	failures, _ := decoded["status_failures"].([]any)
+	if len(failures) != 1 || failures[0] != "resource" {
+		t.Errorf("Expected status_failures [resource], got %v", decoded["status_failures"])
+	}
+	if program, _ := decoded["program"].(map[string]any); program["output"] != "ok" {
+		t.Errorf("Expected program output 'ok', got %v", decoded["program"])
+	}
+}
@@ -5,10 +5,16 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"golang.org/x/net/html/charset"
+	"golang.org/x/net/http/httpproxy"
 	"io"
+	"net"
 	"net/http"
+	"net/url"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/ririnto/monit-exporter/internal/config"
@@ -17,211 +23,400 @@ import (
 
 // Monit represents the top-level XML element <monit>.
 type Monit struct {
-	XMLName  xml.Name  `xml:"monit"`
-	Server   Server    `xml:"server"`
-	Platform Platform  `xml:"platform"`
-	Services []Service `xml:"service"`
+	XMLName  xml.Name  `xml:"monit" json:"-"`
+	Server   Server    `xml:"server" json:"server"`
+	Platform Platform  `xml:"platform" json:"platform"`
+	Services []Service `xml:"service" json:"services"`
+	// Malformed counts the <service> elements skipped because they could not be decoded.
+	Malformed int `xml:"-" json:"-"`
 }
 
 // Server represents the <server> element in the Monit XML.
 type Server struct {
-	ID            string `xml:"id"`
-	Incarnation   int64  `xml:"incarnation"`
-	Version       string `xml:"version"`
-	Uptime        int64  `xml:"uptime"`
-	Poll          int    `xml:"poll"`
-	StartDelay    int    `xml:"startdelay"`
-	Localhostname string `xml:"localhostname"`
-	Controlfile   string `xml:"controlfile"`
-	HTTPD         HTTPD  `xml:"httpd"`
+	ID            string `xml:"id" json:"id"`
+	Incarnation   Int    `xml:"incarnation" json:"incarnation"`
+	Version       string `xml:"version" json:"version"`
+	Uptime        Int    `xml:"uptime" json:"uptime"`
+	Poll          Int    `xml:"poll" json:"poll"`
+	StartDelay    Int    `xml:"startdelay" json:"startdelay"`
+	Localhostname string `xml:"localhostname" json:"localhostname"`
+	Controlfile   string `xml:"controlfile" json:"controlfile"`
+	HTTPD         HTTPD  `xml:"httpd" json:"httpd"`
 }
 
 // HTTPD represents the <httpd> element in the Monit XML.
 type HTTPD struct {
-	Address string `xml:"address"`
-	Port    int    `xml:"port"`
-	SSL     int    `xml:"ssl"`
+	Address string `xml:"address" json:"address"`
+	Port    Int    `xml:"port" json:"port"`
+	SSL     Int    `xml:"ssl" json:"ssl"`
 }
 
 // Platform represents the <platform> element in the Monit XML.
 type Platform struct {
-	Name    string `xml:"name"`
-	Release string `xml:"release"`
-	Version string `xml:"version"`
-	Machine string `xml:"machine"`
-	CPU     int    `xml:"cpu"`
-	Memory  int64  `xml:"memory"`
-	Swap    int64  `xml:"swap"`
+	Name    string `xml:"name" json:"name"`
+	Release string `xml:"release" json:"release"`
+	Version string `xml:"version" json:"version"`
+	Machine string `xml:"machine" json:"machine"`
+	CPU     Int    `xml:"cpu" json:"cpu"`
+	Memory  Int    `xml:"memory" json:"memory"`
+	Swap    Int    `xml:"swap" json:"swap"`
 }
 
 // Service represents the <service> element in the Monit XML.
 type Service struct {
-	Type          int     `xml:"type,attr"`
-	Name          string  `xml:"name"`
-	CollectedSec  int64   `xml:"collected_sec"`
-	CollectedUsec int64   `xml:"collected_usec"`
-	Status        int     `xml:"status"`
-	StatusHint    int     `xml:"status_hint"`
-	Monitor       int     `xml:"monitor"`
-	MonitorMode   int     `xml:"monitormode"`
-	OnReboot      int     `xml:"onreboot"`
-	PendingAction int     `xml:"pendingaction"`
-	Fstype        string  `xml:"fstype,omitempty"`
-	Fsflags       string  `xml:"fsflags,omitempty"`
-	Mode          string  `xml:"mode,omitempty"`
-	UID           int     `xml:"uid,omitempty"`
-	GID           int     `xml:"gid,omitempty"`
-	Block         *Block  `xml:"block,omitempty"`
-	Inode         *Inode  `xml:"inode,omitempty"`
-	Read          string  `xml:"read,omitempty"`
-	Write         string  `xml:"write,omitempty"`
-	Port          *Port   `xml:"port,omitempty"`
-	System        *System `xml:"system,omitempty"`
-	Link          *Link   `xml:"link,omitempty"`
+	Type          Int      `xml:"type,attr" json:"type"`
+	Name          string   `xml:"name" json:"name"`
+	CollectedSec  Int      `xml:"collected_sec" json:"collected_sec"`
+	CollectedUsec Int      `xml:"collected_usec" json:"collected_usec"`
+	Status        Int      `xml:"status" json:"status"`
+	StatusHint    Int      `xml:"status_hint" json:"status_hint"`
+	Monitor       Int      `xml:"monitor" json:"monitor"`
+	MonitorMode   Int      `xml:"monitormode" json:"monitormode"`
+	OnReboot      Int      `xml:"onreboot" json:"onreboot"`
+	PendingAction Int      `xml:"pendingaction" json:"pendingaction"`
+	Fstype        string   `xml:"fstype,omitempty" json:"fstype,omitempty"`
+	Fsflags       string   `xml:"fsflags,omitempty" json:"fsflags,omitempty"`
+	Mode          string   `xml:"mode,omitempty" json:"mode,omitempty"`
+	UID           Int      `xml:"uid,omitempty" json:"uid,omitempty"`
+	GID           Int      `xml:"gid,omitempty" json:"gid,omitempty"`
+	Block         *Block   `xml:"block,omitempty" json:"block,omitempty"`
+	Inode         *Inode   `xml:"inode,omitempty" json:"inode,omitempty"`
+	Read          string   `xml:"read,omitempty" json:"read,omitempty"`
+	Write         string   `xml:"write,omitempty" json:"write,omitempty"`
+	Port          *Port    `xml:"port,omitempty" json:"port,omitempty"`
+	System        *System  `xml:"system,omitempty" json:"system,omitempty"`
+	Link          *Link    `xml:"link,omitempty" json:"link,omitempty"`
+	Program       *Program `xml:"program,omitempty" json:"program,omitempty"`
 }
 
 // Block represents the <block> element under a filesystem service.
 type Block struct {
-	Percent float64 `xml:"percent"`
-	Usage   float64 `xml:"usage"`
-	Total   float64 `xml:"total"`
+	Percent Float `xml:"percent" json:"percent"`
+	Usage   Float `xml:"usage" json:"usage"`
+	Total   Float `xml:"total" json:"total"`
 }
 
 // Inode represents the <inode> element under a filesystem service.
 type Inode struct {
-	Percent float64 `xml:"percent"`
-	Usage   int     `xml:"usage"`
-	Total   int     `xml:"total"`
+	Percent Float `xml:"percent" json:"percent"`
+	Usage   Int   `xml:"usage" json:"usage"`
+	Total   Int   `xml:"total" json:"total"`
 }
 
 // Port represents the <port> element, typically for remote host checks.
 type Port struct {
-	Hostname     string      `xml:"hostname"`
-	Portnumber   int         `xml:"portnumber"`
-	Request      string      `xml:"request"`
-	Protocol     string      `xml:"protocol"`
-	Type         string      `xml:"type"`
-	Responsetime float64     `xml:"responsetime"`
-	Certificate  Certificate `xml:"certificate"`
+	Hostname     string       `xml:"hostname" json:"hostname"`
+	Portnumber   Int          `xml:"portnumber" json:"portnumber"`
+	Request      string       `xml:"request" json:"request"`
+	Protocol     string       `xml:"protocol" json:"protocol"`
+	Type         string       `xml:"type" json:"type"`
+	Responsetime Float        `xml:"responsetime" json:"responsetime"`
+	Certificate  *Certificate `xml:"certificate,omitempty" json:"certificate,omitempty"`
 }
 
 // Certificate represents the <certificate> element under <port>.
 type Certificate struct {
-	Valid int `xml:"valid"`
+	Valid Int `xml:"valid" json:"valid"`
 }
 
 // System represents the <system> element, usually present in type="5" (System) services.
 type System struct {
-	Load   Load   `xml:"load"`
-	CPU    CPU    `xml:"cpu"`
-	Memory Memory `xml:"memory"`
-	Swap   Swap   `xml:"swap"`
+	Load   Load   `xml:"load" json:"load"`
+	CPU    CPU    `xml:"cpu" json:"cpu"`
+	Memory Memory `xml:"memory" json:"memory"`
+	Swap   Swap   `xml:"swap" json:"swap"`
 }
 
 // Load represents the <load> element under <system>.
 type Load struct {
-	Avg01 float64 `xml:"avg01"`
-	Avg05 float64 `xml:"avg05"`
-	Avg15 float64 `xml:"avg15"`
+	Avg01 Float `xml:"avg01" json:"avg01"`
+	Avg05 Float `xml:"avg05" json:"avg05"`
+	Avg15 Float `xml:"avg15" json:"avg15"`
 }
 
 // CPU represents the <cpu> element under <system>.
 type CPU struct {
-	User   float64 `xml:"user"`
-	System float64 `xml:"system"`
-	Wait   float64 `xml:"wait"`
+	User   Float `xml:"user" json:"user"`
+	System Float `xml:"system" json:"system"`
+	Wait   Float `xml:"wait" json:"wait"`
 }
 
 // Memory represents the <memory> element under <system>.
 type Memory struct {
-	Percent  float64 `xml:"percent"`
-	Kilobyte int     `xml:"kilobyte"`
+	Percent  Float `xml:"percent" json:"percent"`
+	Kilobyte Int   `xml:"kilobyte" json:"kilobyte"`
 }
 
 // Swap represents the <swap> element under <system>.
 type Swap struct {
-	Percent  float64 `xml:"percent"`
-	Kilobyte int     `xml:"kilobyte"`
+	Percent  Float `xml:"percent" json:"percent"`
+	Kilobyte Int   `xml:"kilobyte" json:"kilobyte"`
 }
 
 // Link represents the <link> element under a network service.
 type Link struct {
-	State    int      `xml:"state"`
-	Speed    int64    `xml:"speed"`
-	Duplex   int      `xml:"duplex"`
-	Download Download `xml:"download"`
-	Upload   Upload   `xml:"upload"`
+	State    Int      `xml:"state" json:"state"`
+	Speed    Int      `xml:"speed" json:"speed"`
+	Duplex   Int      `xml:"duplex" json:"duplex"`
+	Download Download `xml:"download" json:"download"`
+	Upload   Upload   `xml:"upload" json:"upload"`
 }
 
 // Download represents the <download> element under <link>.
 type Download struct {
-	Packets Packets `xml:"packets"`
-	Bytes   Bytes   `xml:"bytes"`
-	Errors  Errors  `xml:"errors"`
+	Packets Packets `xml:"packets" json:"packets"`
+	Bytes   Bytes   `xml:"bytes" json:"bytes"`
+	Errors  Errors  `xml:"errors" json:"errors"`
 }
 
 // Upload represents the <upload> element under <link>.
 type Upload struct {
-	Packets Packets `xml:"packets"`
-	Bytes   Bytes   `xml:"bytes"`
-	Errors  Errors  `xml:"errors"`
+	Packets Packets `xml:"packets" json:"packets"`
+	Bytes   Bytes   `xml:"bytes" json:"bytes"`
+	Errors  Errors  `xml:"errors" json:"errors"`
 }
 
 // Packets represents the <packets> element under <download> or <upload>.
 type Packets struct {
-	Now   int `xml:"now"`
-	Total int `xml:"total"`
+	Now   Int `xml:"now" json:"now"`
+	Total Int `xml:"total" json:"total"`
 }
 
 // Bytes represents the <bytes> element under <download> or <upload>.
 type Bytes struct {
-	Now   int `xml:"now"`
-	Total int `xml:"total"`
+	Now   Int `xml:"now" json:"now"`
+	Total Int `xml:"total" json:"total"`
 }
 
 // Errors represents the <errors> element under <download> or <upload>.
 type Errors struct {
-	Now   int `xml:"now"`
-	Total int `xml:"total"`
+	Now   Int `xml:"now" json:"now"`
+	Total Int `xml:"total" json:"total"`
 }
 
-// FetchMonitStatus sends an HTTP GET request to the Monit endpoint and returns the response body.
-func FetchMonitStatus(cfg *config.Config) ([]byte, error) {
-	logrus.Debugf("FetchMonitStatus: MonitScrapeURI=%s, IgnoreSSL=%t", cfg.MonitScrapeURI, cfg.IgnoreSSL)
+// Program represents the <program> element under a program service.
+type Program struct {
+	Started Int    `xml:"started" json:"started"`
+	Status  Int    `xml:"status" json:"status"`
+	Output  string `xml:"output" json:"output"`
+}
+
+// newHTTPClient returns an HTTP client configured for talking to the Monit httpd.
+// For a unix:// scrape URI every connection is dialled to the Unix socket instead.
+func newHTTPClient(cfg *config.Config) *http.Client {
+	tr := &http.Transport{
+		Proxy:           proxyFunc(cfg),
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: cfg.IgnoreSSL},
+	}
+	if u, err := url.Parse(cfg.MonitScrapeURI); err == nil && u.Scheme == "unix" {
+		socket := unixSocketPath(u)
+		tr.Proxy = nil
+		tr.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "unix", socket)
+		}
+	}
+	return &http.Client{Transport: tr}
+}
+
+// unixSocketPath returns the socket path of a unix:// URI. Both absolute (unix:///path)
+// and relative (unix://path) forms are accepted.
+func unixSocketPath(u *url.URL) string {
+	if u.Host != "" && u.Host != "localhost" {
+		return u.Host + u.Path
+	}
+	return u.Path
+}
+
+// requestURL returns the HTTP URL requested for a scrape URI. A unix:// URI, whose path
+// names the socket, maps to http://unix/_status with the query of the URI.
+func requestURL(u *url.URL) *url.URL {
+	if u.Scheme != "unix" {
+		return u
+	}
+	return &url.URL{Scheme: "http", Host: "unix", Path: "/_status", RawQuery: u.RawQuery}
+}
+
+// proxyFunc selects the proxy for Monit requests: cfg.MonitProxyURL when set, otherwise
+// HTTP_PROXY/HTTPS_PROXY from the environment. NO_PROXY is honoured in both cases.
+func proxyFunc(cfg *config.Config) func(*http.Request) (*url.URL, error) {
+	proxy := httpproxy.FromEnvironment()
+	if cfg.MonitProxyURL != "" {
+		proxy.HTTPProxy, proxy.HTTPSProxy = cfg.MonitProxyURL, cfg.MonitProxyURL
+	}
+	fn := proxy.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return fn(req.URL)
+	}
+}
+
+// authorize adds the configured headers and credentials to a request sent to Monit.
+// A bearer token, read from cfg.MonitBearerTokenFile on every request so it can be
+// rotated, takes precedence over basic auth. Basic auth is only sent for a configured
+// user and never replaces an Authorization header from cfg.MonitHeaders.
+func authorize(req *http.Request, cfg *config.Config) error {
+	for name, value := range cfg.MonitHeaders {
+		if strings.EqualFold(name, "Host") {
+			req.Host = value
+			continue
+		}
+		req.Header.Set(name, value)
+	}
+	if cfg.MonitBearerTokenFile != "" {
+		data, err := os.ReadFile(cfg.MonitBearerTokenFile)
+		if err != nil {
+			return fmt.Errorf("unable to read Monit bearer token file: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(data)))
+		return nil
+	}
+	if cfg.MonitUser != "" && req.Header.Get("Authorization") == "" {
+		req.SetBasicAuth(cfg.MonitUser, cfg.MonitPassword)
+	}
+	return nil
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+// ErrResponseTooLarge is returned when the Monit status exceeds the configured maximum body size.
+var ErrResponseTooLarge = errors.New("monit status exceeds the maximum body size")
 
-	req, err := http.NewRequestWithContext(ctx, "GET", cfg.MonitScrapeURI, nil)
+// maxBytesReader fails with ErrResponseTooLarge once more than remaining bytes are read from r.
+type maxBytesReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+// Read reads from the underlying reader, allowing at most one byte past the limit to detect an overflow.
+func (m *maxBytesReader) Read(p []byte) (int, error) {
+	if m.remaining < 0 {
+		return 0, ErrResponseTooLarge
+	}
+	if int64(len(p)) > m.remaining+1 {
+		p = p[:m.remaining+1]
+	}
+	n, err := m.r.Read(p)
+	if int64(n) > m.remaining {
+		n = int(m.remaining)
+		m.remaining = -1
+		return n, ErrResponseTooLarge
+	}
+	m.remaining -= int64(n)
+	return n, err
+}
+
+// limitBody wraps r so that reading more than limit bytes fails; a limit of 0 disables the check.
+func limitBody(r io.Reader, limit int64) io.Reader {
+	if limit <= 0 {
+		return r
+	}
+	return &maxBytesReader{r: r, remaining: limit}
+}
+
+// statusBody is an open Monit status whose Close also releases the request context.
+type statusBody struct {
+	io.Reader
+	closer io.Closer
+	cancel context.CancelFunc
+}
+
+// Close closes the underlying response body or file.
+func (b *statusBody) Close() error {
+	defer b.cancel()
+	return b.closer.Close()
+}
+
+// openStatusFile opens Monit XML from a file:// URI. Both absolute (file:///path)
+// and relative (file://path) forms are accepted.
+func openStatusFile(u *url.URL, limit int64) (io.ReadCloser, error) {
+	path := u.Path
+	if u.Host != "" && u.Host != "localhost" {
+		path = u.Host + u.Path
+	}
+	logrus.Debugf("openStatusFile: reading Monit status from %s", path)
+
+	f, err := os.Open(path)
 	if err != nil {
-		logrus.Errorf("FetchMonitStatus: failed to create HTTP request: %v", err)
-		return nil, fmt.Errorf("unable to create request: %w", err)
+		logrus.Errorf("openStatusFile: failed to open file: %v", err)
+		return nil, fmt.Errorf("unable to read Monit status file: %w", err)
 	}
-	req.SetBasicAuth(cfg.MonitUser, cfg.MonitPassword)
+	if info, err := f.Stat(); err == nil && limit > 0 && info.Size() > limit {
+		_ = f.Close()
+		logrus.Errorf("openStatusFile: file size %d exceeds the limit of %d bytes", info.Size(), limit)
+		return nil, fmt.Errorf("%w (%d > %d bytes)", ErrResponseTooLarge, info.Size(), limit)
+	}
+	return &statusBody{Reader: limitBody(f, limit), closer: f, cancel: func() {}}, nil
+}
 
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: cfg.IgnoreSSL},
+// openMonitStatus sends an HTTP GET request to the Monit endpoint and returns the response body,
+// limited to cfg.MaxBodySize bytes. A file:// URI opens the status from a local file instead,
+// and a unix:// URI requests it over the Unix socket Monit's httpd is bound to.
+// The request is bounded by ctx as well as the per-request timeout.
+func openMonitStatus(ctx context.Context, cfg *config.Config) (io.ReadCloser, error) {
+	logrus.Debugf("openMonitStatus: MonitScrapeURI=%s, IgnoreSSL=%t", cfg.MonitScrapeURI, cfg.IgnoreSSL)
+
+	u, err := url.Parse(cfg.MonitScrapeURI)
+	if err != nil {
+		logrus.Errorf("openMonitStatus: invalid scrape URI: %v", err)
+		return nil, fmt.Errorf("unable to parse Monit URI: %w", err)
+	}
+	if u.Scheme == "file" {
+		return openStatusFile(u, cfg.MaxBodySize)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", requestURL(u).String(), nil)
+	if err != nil {
+		cancel()
+		logrus.Errorf("openMonitStatus: failed to create HTTP request: %v", err)
+		return nil, fmt.Errorf("unable to create request: %w", err)
+	}
+	if err := authorize(req, cfg); err != nil {
+		cancel()
+		logrus.Errorf("openMonitStatus: %v", err)
+		return nil, err
 	}
-	client := &http.Client{Transport: tr}
 
-	logrus.Debug("FetchMonitStatus: sending request to Monit")
+	client := newHTTPClient(cfg)
+
+	logrus.Debug("openMonitStatus: sending request to Monit")
 	resp, err := client.Do(req)
 	if err != nil {
-		logrus.Errorf("FetchMonitStatus: HTTP request failed: %v", err)
+		cancel()
+		logrus.Errorf("openMonitStatus: HTTP request failed: %v", err)
 		return nil, fmt.Errorf("unable to fetch Monit status: %w", err)
 	}
-	defer func() {
-		if cerr := resp.Body.Close(); cerr != nil {
-			logrus.Warnf("FetchMonitStatus: failed to close response body: %v", cerr)
-		}
-	}()
+	body := &statusBody{Reader: limitBody(resp.Body, cfg.MaxBodySize), closer: resp.Body, cancel: cancel}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		logrus.Errorf("FetchMonitStatus: non-2xx status code: %d", resp.StatusCode)
+		closeStatus(body)
+		logrus.Errorf("openMonitStatus: non-2xx status code: %d", resp.StatusCode)
 		return nil, fmt.Errorf("monit returned non-2xx status code: %d", resp.StatusCode)
 	}
+	if cfg.MaxBodySize > 0 && resp.ContentLength > cfg.MaxBodySize {
+		closeStatus(body)
+		logrus.Errorf("openMonitStatus: Content-Length %d exceeds the limit of %d bytes", resp.ContentLength, cfg.MaxBodySize)
+		return nil, fmt.Errorf("%w (%d > %d bytes)", ErrResponseTooLarge, resp.ContentLength, cfg.MaxBodySize)
+	}
+	return body, nil
+}
+
+// closeStatus closes an open Monit status, logging any error.
+func closeStatus(body io.Closer) {
+	if err := body.Close(); err != nil {
+		logrus.Warnf("closeStatus: failed to close Monit status: %v", err)
+	}
+}
+
+// FetchMonitStatus sends an HTTP GET request to the Monit endpoint and returns the response body.
+// A file:// URI reads the status from a local file instead.
+func FetchMonitStatus(cfg *config.Config) ([]byte, error) {
+	body, err := openMonitStatus(context.Background(), cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer closeStatus(body)
 
-	data, err := io.ReadAll(resp.Body)
+	data, err := io.ReadAll(body)
 	if err != nil {
 		logrus.Errorf("FetchMonitStatus: failed to read response body: %v", err)
 		return nil, fmt.Errorf("unable to read Monit status: %w", err)
@@ -230,18 +425,164 @@ func FetchMonitStatus(cfg *config.Config) ([]byte, error) {
 	return data, nil
 }
 
+// StreamMonitStatus fetches the Monit status like FetchMonitStatus but decodes it while it is
+// read from the response body, so the raw document is never held in memory as a whole.
+func StreamMonitStatus(cfg *config.Config) (Monit, error) {
+	return StreamMonitStatusContext(context.Background(), cfg)
+}
+
+// StreamMonitStatusContext is StreamMonitStatus with the request bounded by ctx.
+func StreamMonitStatusContext(ctx context.Context, cfg *config.Config) (Monit, error) {
+	body, err := openMonitStatus(ctx, cfg)
+	if err != nil {
+		return Monit{}, err
+	}
+	defer closeStatus(body)
+
+	return DecodeMonitStatus(body)
+}
+
 // ParseMonitStatus parses the XML data and returns a Monit struct.
 func ParseMonitStatus(data []byte) (Monit, error) {
-	logrus.Debug("ParseMonitStatus: starting XML parsing")
-	var statusChunk Monit
-	reader := bytes.NewReader(data)
-	decoder := xml.NewDecoder(reader)
+	return DecodeMonitStatus(bytes.NewReader(data))
+}
+
+// DecodeMonitStatus decodes the Monit XML read from r one <service> element at a time.
+// A <service> element that cannot be decoded is skipped and counted in Malformed
+// instead of failing the document.
+func DecodeMonitStatus(r io.Reader) (Monit, error) {
+	logrus.Debug("DecodeMonitStatus: starting XML parsing")
+	decoder := xml.NewDecoder(r)
 	decoder.CharsetReader = charset.NewReaderLabel
 
-	if err := decoder.Decode(&statusChunk); err != nil {
-		logrus.Errorf("ParseMonitStatus: XML parsing failed: %v", err)
+	statusChunk, err := decodeStatus(decoder)
+	if err != nil {
+		logrus.Errorf("DecodeMonitStatus: XML parsing failed: %v", err)
+		if errors.Is(err, ErrResponseTooLarge) {
+			return Monit{}, err
+		}
 		return Monit{}, fmt.Errorf("failed to parse Monit XML: %w", err)
 	}
-	logrus.Debugf("ParseMonitStatus: successfully parsed. Services count=%d", len(statusChunk.Services))
+	logrus.Debugf("DecodeMonitStatus: successfully parsed. Services count=%d, malformed=%d",
+		len(statusChunk.Services), statusChunk.Malformed)
 	return statusChunk, nil
 }
+
+// decodeStatus walks the children of the <monit> root element. Malformed XML fails the
+// whole document, while a child whose values cannot be decoded is skipped on its own.
+func decodeStatus(decoder *xml.Decoder) (Monit, error) {
+	var status Monit
+	root, err := nextStartElement(decoder)
+	if err != nil {
+		return Monit{}, err
+	}
+	if root.Name.Local != "monit" {
+		return Monit{}, fmt.Errorf("expected <monit> root element, got <%s>", root.Name.Local)
+	}
+	status.XMLName = root.Name
+
+	for {
+		tok, err := decoder.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return Monit{}, io.ErrUnexpectedEOF
+			}
+			return Monit{}, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			element, err := readElement(decoder, t)
+			if err != nil {
+				return Monit{}, err
+			}
+			switch t.Name.Local {
+			case "server":
+				if err := element.decode(&status.Server); err != nil {
+					logrus.Warnf("decodeStatus: ignoring malformed <server>: %v", err)
+				}
+			case "platform":
+				if err := element.decode(&status.Platform); err != nil {
+					logrus.Warnf("decodeStatus: ignoring malformed <platform>: %v", err)
+				}
+			case "service":
+				var service Service
+				if err := element.decode(&service); err != nil {
+					logrus.Warnf("decodeStatus: skipping malformed service %q: %v", element.serviceName(), err)
+					status.Malformed++
+					continue
+				}
+				status.Services = append(status.Services, service)
+			}
+		case xml.EndElement:
+			return status, nil
+		}
+	}
+}
+
+// nextStartElement returns the first start element, skipping the prolog.
+func nextStartElement(decoder *xml.Decoder) (xml.StartElement, error) {
+	for {
+		tok, err := decoder.Token()
+		if err != nil {
+			return xml.StartElement{}, err
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			return start, nil
+		}
+	}
+}
+
+// element holds the tokens of a single XML element so it can be decoded on its own.
+type element struct {
+	tokens []xml.Token
+	next   int
+}
+
+// readElement consumes the element opened by start from decoder and returns its tokens.
+func readElement(decoder *xml.Decoder, start xml.StartElement) (*element, error) {
+	e := &element{tokens: []xml.Token{start.Copy()}}
+	for depth := 1; depth > 0; {
+		tok, err := decoder.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		switch tok.(type) {
+		case xml.StartElement:
+			depth++
+		case xml.EndElement:
+			depth--
+		}
+		e.tokens = append(e.tokens, xml.CopyToken(tok))
+	}
+	return e, nil
+}
+
+// Token implements xml.TokenReader over the recorded tokens.
+func (e *element) Token() (xml.Token, error) {
+	if e.next >= len(e.tokens) {
+		return nil, io.EOF
+	}
+	e.next++
+	return e.tokens[e.next-1], nil
+}
+
+// decode unmarshals the recorded element into v.
+func (e *element) decode(v any) error {
+	e.next = 0
+	return xml.NewTokenDecoder(e).Decode(v)
+}
+
+// serviceName returns the text of the element's <name> child, for logging.
+func (e *element) serviceName() string {
+	for i, tok := range e.tokens[:len(e.tokens)-1] {
+		if start, ok := tok.(xml.StartElement); ok && start.Name.Local == "name" {
+			if text, ok := e.tokens[i+1].(xml.CharData); ok {
+				return string(text)
+			}
+		}
+	}
+	return ""
+}
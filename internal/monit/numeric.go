@@ -0,0 +1,50 @@
+package monit
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Int is an integer Monit value. It is decoded leniently: surrounding whitespace is
+// ignored, an empty element is zero and a fractional value such as "1.000" is truncated.
+type Int int64
+
+// UnmarshalText decodes an integer from element or attribute text.
+func (i *Int) UnmarshalText(text []byte) error {
+	s := strings.TrimSpace(string(text))
+	if s == "" {
+		*i = 0
+		return nil
+	}
+	if v, err := strconv.ParseInt(s, 10, 64); err == nil {
+		*i = Int(v)
+		return nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+		return fmt.Errorf("invalid integer %q", s)
+	}
+	*i = Int(f)
+	return nil
+}
+
+// Float is a floating-point Monit value. It is decoded leniently: surrounding whitespace
+// is ignored and an empty element is zero. NaN and infinities are rejected.
+type Float float64
+
+// UnmarshalText decodes a number from element or attribute text.
+func (f *Float) UnmarshalText(text []byte) error {
+	s := strings.TrimSpace(string(text))
+	if s == "" {
+		*f = 0
+		return nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return fmt.Errorf("invalid number %q", s)
+	}
+	*f = Float(v)
+	return nil
+}
@@ -0,0 +1,99 @@
+package monit
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestInt_UnmarshalText verifies the lenient integer decoding.
+func TestInt_UnmarshalText(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    Int
+		wantErr bool
+	}{
+		{"42", 42, false},
+		{" 7\n", 7, false},
+		{"", 0, false},
+		{"-1", -1, false},
+		{"1.000", 1, false},
+		{"-1.000000", -1, false},
+		{"9223372036854775807", 9223372036854775807, false},
+		{"1e3", 1000, false},
+		{"99999999999999999999", 0, true},
+		{"NaN", 0, true},
+		{"abc", 0, true},
+	}
+	for _, tt := range tests {
+		var got Int
+		err := got.UnmarshalText([]byte(tt.text))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("UnmarshalText(%q): expected error=%t, got %v", tt.text, tt.wantErr, err)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("UnmarshalText(%q): expected %d, got %d", tt.text, tt.want, got)
+		}
+	}
+}
+
+// TestFloat_UnmarshalText verifies the lenient floating-point decoding.
+func TestFloat_UnmarshalText(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    Float
+		wantErr bool
+	}{
+		{"42.5", 42.5, false},
+		{" 0.25 ", 0.25, false},
+		{"", 0, false},
+		{"-1.000", -1, false},
+		{"NaN", 0, true},
+		{"+Inf", 0, true},
+		{"1e400", 0, true},
+		{"n/a", 0, true},
+	}
+	for _, tt := range tests {
+		var got Float
+		err := got.UnmarshalText([]byte(tt.text))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("UnmarshalText(%q): expected error=%t, got %v", tt.text, tt.wantErr, err)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("UnmarshalText(%q): expected %f, got %f", tt.text, tt.want, got)
+		}
+	}
+}
+
+// FuzzInt_UnmarshalText checks that any accepted integer text round-trips through JSON.
+func FuzzInt_UnmarshalText(f *testing.F) {
+	for _, seed := range []string{"0", "-1.000000", " 12 ", "9223372036854775807", "1e18", ""} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, text string) {
+		var v Int
+		if err := v.UnmarshalText([]byte(text)); err != nil {
+			return
+		}
+		if _, err := json.Marshal(v); err != nil {
+			t.Errorf("Accepted %q but cannot encode %d: %v", text, v, err)
+		}
+	})
+}
+
+// FuzzFloat_UnmarshalText checks that any accepted number can be encoded as JSON.
+func FuzzFloat_UnmarshalText(f *testing.F) {
+	for _, seed := range []string{"0", "42.5", "-1.000", "1e308", "", "NaN"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, text string) {
+		var v Float
+		if err := v.UnmarshalText([]byte(text)); err != nil {
+			return
+		}
+		if _, err := json.Marshal(v); err != nil {
+			t.Errorf("Accepted %q but cannot encode %f: %v", text, v, err)
+		}
+	})
+}
@@ -0,0 +1,279 @@
+package monittest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ririnto/monit-exporter/internal/monit"
+)
+
+// securityTokenName is the cookie and form field carrying Monit's CSRF token.
+const securityTokenName = "securitytoken"
+
+// Options configures the simulated Monit.
+type Options struct {
+	// Hostname is reported as the server's localhostname and names the System service.
+	Hostname string
+	// Version is the reported Monit version.
+	Version string
+	// Processes adds this many extra process services named worker-N.
+	Processes int
+	// RotateFailures reports a different failing service on every status request.
+	RotateFailures bool
+	// Username and Password enable basic auth when Username is set.
+	Username string
+	Password string
+	// Latency delays every status response.
+	Latency time.Duration
+	// ErrorEvery answers every n-th status request with 503 Service Unavailable.
+	ErrorEvery int
+}
+
+// failureBits is the status bit reported for each service type when it fails.
+var failureBits = map[monit.Int]monit.Int{
+	0: 0x2,
+	1: 0x8,
+	2: 0x1,
+	3: 0x200,
+	4: 0x20,
+	5: 0x2,
+	6: 0x40,
+	7: 0x200000,
+	8: 0x800000,
+}
+
+// Simulator is an http.Handler behaving like the Monit httpd.
+type Simulator struct {
+	opts    Options
+	now     func() time.Time
+	started time.Time
+
+	mutex       sync.Mutex
+	requests    int
+	unmonitored map[string]bool
+}
+
+// NewSimulator returns a simulator for opts, filling in the default hostname and version.
+func NewSimulator(opts Options) *Simulator {
+	if opts.Hostname == "" {
+		opts.Hostname = "fake-monit"
+	}
+	if opts.Version == "" {
+		opts.Version = "5.33.0"
+	}
+	return &Simulator{opts: opts, now: time.Now, started: time.Now(), unmonitored: map[string]bool{}}
+}
+
+// NewServer starts an HTTP test server backed by a new simulator. The caller must Close it.
+func NewServer(opts Options) *httptest.Server {
+	return httptest.NewServer(NewSimulator(opts))
+}
+
+// NewTLSServer starts an HTTPS test server backed by a new simulator. The caller must Close it.
+func NewTLSServer(opts Options) *httptest.Server {
+	return httptest.NewTLSServer(NewSimulator(opts))
+}
+
+// Requests returns the number of status requests served so far.
+func (s *Simulator) Requests() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.requests
+}
+
+// vary returns base oscillating by amplitude with the request count, so values move between scrapes.
+func vary(base, amplitude float64, request int) monit.Float {
+	return monit.Float(math.Round((base+amplitude*math.Sin(float64(request)/5))*10) / 10)
+}
+
+// Status returns the snapshot reported for the given status request number.
+func (s *Simulator) Status(request int) monit.Monit {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.status(request)
+}
+
+// status builds the snapshot; the caller must hold the mutex.
+func (s *Simulator) status(request int) monit.Monit {
+	now := s.now()
+	services := []monit.Service{
+		{Type: 0, Name: "rootfs", Fstype: "ext4", Fsflags: "rw,relatime", Mode: "755",
+			Block: &monit.Block{Percent: vary(42, 3, request), Usage: vary(21504, 1536, request), Total: 51200},
+			Inode: &monit.Inode{Percent: 3.1, Usage: 102400, Total: 3276800}},
+		{Type: 1, Name: "varlog", Mode: "755"},
+		{Type: 2, Name: "syslog", Mode: "640", GID: 4},
+		{Type: 3, Name: "nginx"},
+		{Type: 4, Name: "api.example.com", Port: &monit.Port{
+			Hostname: "api.example.com", Portnumber: 443, Request: "/health", Protocol: "HTTP", Type: "TCP",
+			Responsetime: vary(0.05, 0.03, request), Certificate: &monit.Certificate{Valid: 45}}},
+		{Type: 5, Name: s.opts.Hostname, System: &monit.System{
+			Load:   monit.Load{Avg01: vary(0.8, 0.6, request), Avg05: vary(0.7, 0.3, request), Avg15: vary(0.6, 0.1, request)},
+			CPU:    monit.CPU{User: vary(12, 8, request), System: vary(4, 2, request), Wait: vary(0.5, 0.4, request)},
+			Memory: monit.Memory{Percent: vary(61, 5, request), Kilobyte: monit.Int(vary(2500000, 200000, request))},
+			Swap:   monit.Swap{Percent: 1.2, Kilobyte: 49152}}},
+		{Type: 6, Name: "fifo", Mode: "600"},
+		{Type: 7, Name: "backup", Program: &monit.Program{Started: monit.Int(now.Add(-time.Hour).Unix()), Output: "backup completed"}},
+		{Type: 8, Name: "eth0", Link: &monit.Link{State: 1, Speed: 1000000000, Duplex: 1,
+			Download: monit.Download{Bytes: monit.Bytes{Now: 125000, Total: monit.Int(1048576 * request)}, Packets: monit.Packets{Now: 120, Total: monit.Int(1000 * request)}},
+			Upload:   monit.Upload{Bytes: monit.Bytes{Now: 64000, Total: monit.Int(524288 * request)}, Packets: monit.Packets{Now: 80, Total: monit.Int(600 * request)}}}},
+	}
+	for i := range s.opts.Processes {
+		services = append(services, monit.Service{Type: 3, Name: fmt.Sprintf("worker-%d", i+1)})
+	}
+
+	for i := range services {
+		service := &services[i]
+		service.CollectedSec = monit.Int(now.Unix())
+		service.CollectedUsec = monit.Int(now.Nanosecond() / 1000)
+		service.Monitor = 1
+		if s.unmonitored[service.Name] {
+			service.Monitor = 0
+		}
+		if s.opts.RotateFailures && request%len(services) == i {
+			service.Status = failureBits[service.Type]
+		}
+	}
+
+	return monit.Monit{
+		Server: monit.Server{
+			ID:            "0f5e2a6c9b8d4e3f",
+			Incarnation:   monit.Int(s.started.Unix()),
+			Version:       s.opts.Version,
+			Uptime:        monit.Int(now.Sub(s.started).Seconds()),
+			Poll:          30,
+			Localhostname: s.opts.Hostname,
+			Controlfile:   "/etc/monit/monitrc",
+			HTTPD:         monit.HTTPD{Address: "127.0.0.1", Port: 2812},
+		},
+		Platform: monit.Platform{Name: "Linux", Release: "6.1.0", Version: "#1 SMP", Machine: "x86_64",
+			CPU: 4, Memory: 4030000, Swap: 2097148},
+		Services: services,
+	}
+}
+
+// XML returns the Monit XML document for the given status request number.
+func (s *Simulator) XML(request int) ([]byte, error) {
+	data, err := xml.MarshalIndent(s.Status(request), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode Monit status: %w", err)
+	}
+	return append([]byte(`<?xml version="1.0" encoding="ISO-8859-1"?>`+"\n"), data...), nil
+}
+
+// ServeHTTP serves /_status like Monit and accepts service actions posted to /{service}.
+func (s *Simulator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.opts.Username != "" {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != s.opts.Username || password != s.opts.Password {
+			w.Header().Set("WWW-Authenticate", `Basic realm="monit"`)
+			http.Error(w, "You are not authorized to access monit", http.StatusUnauthorized)
+			return
+		}
+	}
+	if r.URL.Path == "/_status" {
+		s.serveStatus(w, r)
+		return
+	}
+	s.serveAction(w, r)
+}
+
+// serveStatus answers a status request, applying the configured latency and errors.
+func (s *Simulator) serveStatus(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	s.requests++
+	request := s.requests
+	s.mutex.Unlock()
+
+	if s.opts.Latency > 0 {
+		select {
+		case <-time.After(s.opts.Latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+	if s.opts.ErrorEvery > 0 && request%s.opts.ErrorEvery == 0 {
+		http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	data, err := s.XML(request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/xml")
+	_, _ = w.Write(data)
+}
+
+// serveAction implements Monit's service page: GET issues the security token and
+// POST applies the action, where stop and unmonitor stop monitoring the service.
+func (s *Simulator) serveAction(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Path[1:]
+	if !slices.ContainsFunc(s.Status(0).Services, func(service monit.Service) bool { return service.Name == name }) {
+		http.NotFound(w, r)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		http.SetCookie(w, &http.Cookie{Name: securityTokenName, Value: "fake-monit-token", Path: "/"})
+		_, _ = fmt.Fprintf(w, "<html><body>%s</body></html>", name)
+	case http.MethodPost:
+		cookie, err := r.Cookie(securityTokenName)
+		if err != nil || r.PostFormValue(securityTokenName) != cookie.Value {
+			http.Error(w, "Invalid security token", http.StatusForbidden)
+			return
+		}
+		action := r.PostFormValue("action")
+		if !slices.Contains(monit.Actions, action) {
+			http.Error(w, "Invalid action", http.StatusBadRequest)
+			return
+		}
+		s.mutex.Lock()
+		s.unmonitored[name] = action == "stop" || action == "unmonitor"
+		s.mutex.Unlock()
+		_, _ = fmt.Fprintf(w, "<html><body>%s %s</body></html>", action, name)
+	default:
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// SelfSignedCertificate returns a throwaway certificate valid for hosts, for serving TLS without key files.
+func SelfSignedCertificate(hosts ...string) (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("unable to generate key: %w", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "fake-monit"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(365 * 24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	for host := range slices.Values(hosts) {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("unable to create certificate: %w", err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+}
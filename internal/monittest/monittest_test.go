@@ -0,0 +1,138 @@
+package monittest
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ririnto/monit-exporter/internal/config"
+	"github.com/ririnto/monit-exporter/internal/monit"
+)
+
+// TestSimulator_Status verifies that every service type is reported and parses back.
+func TestSimulator_Status(t *testing.T) {
+	t.Log("Testing the simulated Monit status")
+	server := NewServer(Options{Hostname: "web-1", Processes: 2})
+	defer server.Close()
+
+	data, err := monit.FetchMonitStatus(&config.Config{MonitScrapeURI: server.URL + "/_status?format=xml&level=full"})
+	if err != nil {
+		t.Fatalf("FetchMonitStatus failed: %v", err)
+	}
+	status, err := monit.ParseMonitStatus(data)
+	if err != nil {
+		t.Fatalf("ParseMonitStatus failed: %v", err)
+	}
+	if status.Server.Localhostname != "web-1" || status.Server.Version != "5.33.0" {
+		t.Errorf("Expected web-1 running 5.33.0, got %+v", status.Server)
+	}
+	types := map[monit.Int]bool{}
+	for _, service := range status.Services {
+		types[service.Type] = true
+		if service.Status != 0 || service.Monitor != 1 {
+			t.Errorf("Expected %s to be ok and monitored, got status %d monitor %d", service.Name, service.Status, service.Monitor)
+		}
+	}
+	for serviceType := range monit.Int(9) {
+		if !types[serviceType] {
+			t.Errorf("Expected a service of type %d", serviceType)
+		}
+	}
+	if len(status.Services) != 11 {
+		t.Errorf("Expected 9 services plus 2 workers, got %d", len(status.Services))
+	}
+}
+
+// TestSimulator_RotateFailures verifies that exactly one service fails per request, in turn.
+func TestSimulator_RotateFailures(t *testing.T) {
+	t.Log("Testing rotating failures")
+	simulator := NewSimulator(Options{RotateFailures: true})
+	first, second := simulator.Status(1), simulator.Status(2)
+
+	failing := func(status monit.Monit) []string {
+		var names []string
+		for _, service := range status.Services {
+			if service.Status != 0 {
+				names = append(names, service.Name)
+			}
+		}
+		return names
+	}
+	if names := failing(first); len(names) != 1 || names[0] != "varlog" {
+		t.Errorf("Expected varlog to fail on request 1, got %v", names)
+	}
+	if names := failing(second); len(names) != 1 || names[0] != "syslog" {
+		t.Errorf("Expected syslog to fail on request 2, got %v", names)
+	}
+}
+
+// TestSimulator_Responses verifies basic auth, injected errors and latency.
+func TestSimulator_Responses(t *testing.T) {
+	t.Log("Testing basic auth, errors and latency")
+	server := NewTLSServer(Options{Username: "admin", Password: "monit", ErrorEvery: 2, Latency: 20 * time.Millisecond})
+	defer server.Close()
+
+	get := func(user, password string) int {
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/_status?format=xml", nil)
+		if err != nil {
+			t.Fatalf("Failed to create request: %v", err)
+		}
+		req.SetBasicAuth(user, password)
+		resp, err := server.Client().Do(req)
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if code := get("admin", "wrong"); code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for a wrong password, got %d", code)
+	}
+	start := time.Now()
+	if code := get("admin", "monit"); code != http.StatusOK {
+		t.Errorf("Expected 200 for the first request, got %d", code)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("Expected the response to be delayed, took %v", elapsed)
+	}
+	if code := get("admin", "monit"); code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 for the second request, got %d", code)
+	}
+}
+
+// TestSimulator_Control verifies that service actions sent by monit.ControlService are applied.
+func TestSimulator_Control(t *testing.T) {
+	t.Log("Testing service control")
+	simulator := NewSimulator(Options{})
+	server := httptest.NewServer(simulator)
+	defer server.Close()
+
+	cfg := &config.Config{MonitScrapeURI: server.URL + "/_status?format=xml"}
+	if err := monit.ControlService(cfg, "nginx", "unmonitor"); err != nil {
+		t.Fatalf("ControlService failed: %v", err)
+	}
+	for _, service := range simulator.Status(1).Services {
+		if service.Name == "nginx" && service.Monitor != 0 {
+			t.Errorf("Expected nginx to be unmonitored, got monitor %d", service.Monitor)
+		}
+	}
+	if err := monit.ControlService(cfg, "missing", "start"); err == nil {
+		t.Error("Expected an error for an unknown service, got nil")
+	}
+}
+
+// TestSelfSignedCertificate verifies the generated certificate covers the given hosts.
+func TestSelfSignedCertificate(t *testing.T) {
+	t.Log("Testing SelfSignedCertificate")
+	certificate, err := SelfSignedCertificate("127.0.0.1", "localhost")
+	if err != nil {
+		t.Fatalf("SelfSignedCertificate failed: %v", err)
+	}
+	if len(certificate.Certificate) != 1 || certificate.PrivateKey == nil {
+		t.Errorf("Expected one certificate with a key, got %+v", certificate)
+	}
+}
@@ -0,0 +1,131 @@
+package notifier
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ririnto/monit-exporter/internal/monit"
+)
+
+// State is the coarse state of a service used to detect transitions.
+type State string
+
+// Service states.
+const (
+	StateOK          State = "ok"
+	StateFailed      State = "failed"
+	StateUnmonitored State = "unmonitored"
+	StateAbsent      State = "absent"
+)
+
+// Kind identifies a service state transition.
+type Kind string
+
+// Event kinds.
+const (
+	KindFailed      Kind = "failed"
+	KindRecovered   Kind = "recovered"
+	KindUnmonitored Kind = "unmonitored"
+	KindDisappeared Kind = "disappeared"
+)
+
+// Event describes a state change of one service between two snapshots.
+type Event struct {
+	Kind        Kind      `json:"kind"`
+	Service     string    `json:"service"`
+	ServiceType string    `json:"service_type"`
+	Host        string    `json:"host"`
+	Status      int       `json:"status"`
+	Failures    []string  `json:"failures"`
+	Previous    State     `json:"previous_state"`
+	Current     State     `json:"current_state"`
+	Time        time.Time `json:"time"`
+	Message     string    `json:"message"`
+}
+
+// serviceState returns the coarse state of a service.
+func serviceState(service monit.Service) State {
+	switch {
+	case service.Monitor == 0:
+		return StateUnmonitored
+	case service.Status != 0:
+		return StateFailed
+	default:
+		return StateOK
+	}
+}
+
+// Diff returns the events between two consecutive snapshots. Services missing from the
+// previous snapshot (including every service when previous is nil) are treated as OK,
+// so services already failing or unmonitored are reported once.
+func Diff(previous *monit.Monit, current monit.Monit, now time.Time) []Event {
+	before := map[string]monit.Service{}
+	if previous != nil {
+		for service := range slices.Values(previous.Services) {
+			before[service.Name] = service
+		}
+	}
+	host := current.Server.Localhostname
+
+	var events []Event
+	for service := range slices.Values(current.Services) {
+		from := StateOK
+		if old, ok := before[service.Name]; ok {
+			from = serviceState(old)
+			delete(before, service.Name)
+		}
+		to := serviceState(service)
+		if from == to {
+			continue
+		}
+		kind := KindRecovered
+		switch to {
+		case StateFailed:
+			kind = KindFailed
+		case StateUnmonitored:
+			kind = KindUnmonitored
+		}
+		events = append(events, newEvent(kind, service, host, from, to, now))
+	}
+
+	if previous != nil {
+		for service := range slices.Values(previous.Services) {
+			if _, ok := before[service.Name]; ok {
+				events = append(events, newEvent(KindDisappeared, service, previous.Server.Localhostname, serviceState(service), StateAbsent, now))
+			}
+		}
+	}
+	return events
+}
+
+// newEvent builds an event with its human readable message.
+func newEvent(kind Kind, service monit.Service, host string, from, to State, now time.Time) Event {
+	serviceType, ok := monit.ServiceTypeName(service.Type)
+	if !ok {
+		serviceType = "unknown"
+	}
+	event := Event{
+		Kind:        kind,
+		Service:     service.Name,
+		ServiceType: serviceType,
+		Host:        host,
+		Status:      int(service.Status),
+		Failures:    monit.StatusFailures(service.Status),
+		Previous:    from,
+		Current:     to,
+		Time:        now,
+	}
+	switch kind {
+	case KindFailed:
+		event.Message = fmt.Sprintf("%s %s on %s failed: %s", serviceType, service.Name, host, strings.Join(event.Failures, ", "))
+	case KindRecovered:
+		event.Message = fmt.Sprintf("%s %s on %s recovered (was %s)", serviceType, service.Name, host, from)
+	case KindUnmonitored:
+		event.Message = fmt.Sprintf("%s %s on %s is no longer monitored", serviceType, service.Name, host)
+	case KindDisappeared:
+		event.Message = fmt.Sprintf("%s %s on %s disappeared from Monit", serviceType, service.Name, host)
+	}
+	return event
+}
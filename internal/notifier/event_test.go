@@ -0,0 +1,69 @@
+package notifier
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ririnto/monit-exporter/internal/monit"
+)
+
+// snapshot builds a Monit snapshot from services on host web-1.
+func snapshot(services ...monit.Service) monit.Monit {
+	return monit.Monit{Server: monit.Server{Localhostname: "web-1"}, Services: services}
+}
+
+func service(name string, status, monitor monit.Int) monit.Service {
+	return monit.Service{Type: 3, Name: name, Status: status, Monitor: monitor}
+}
+
+func TestDiff(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	previous := snapshot(
+		service("nginx", 0, 1),
+		service("mysql", 512, 1),
+		service("cron", 0, 1),
+		service("redis", 0, 1),
+		service("sshd", 0, 1),
+	)
+	current := snapshot(
+		service("nginx", 0x8, 1),
+		service("mysql", 0, 1),
+		service("cron", 0, 0),
+		service("sshd", 0, 1),
+	)
+
+	events := Diff(&previous, current, now)
+	expected := []struct {
+		service string
+		kind    Kind
+		from    State
+		to      State
+	}{
+		{"nginx", KindFailed, StateOK, StateFailed},
+		{"mysql", KindRecovered, StateFailed, StateOK},
+		{"cron", KindUnmonitored, StateOK, StateUnmonitored},
+		{"redis", KindDisappeared, StateOK, StateAbsent},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, got %d: %+v", len(expected), len(events), events)
+	}
+	for i, want := range expected {
+		got := events[i]
+		if got.Service != want.service || got.Kind != want.kind || got.Previous != want.from || got.Current != want.to {
+			t.Errorf("Expected %+v, got %+v", want, got)
+		}
+	}
+	if events[0].Message != "Process nginx on web-1 failed: timestamp" {
+		t.Errorf("Unexpected message '%s'", events[0].Message)
+	}
+	if !events[0].Time.Equal(now) || events[0].Host != "web-1" {
+		t.Errorf("Unexpected event metadata %+v", events[0])
+	}
+}
+
+func TestDiff_FirstSnapshot(t *testing.T) {
+	events := Diff(nil, snapshot(service("nginx", 0, 1), service("mysql", 512, 1)), time.Now())
+	if len(events) != 1 || events[0].Service != "mysql" || events[0].Kind != KindFailed {
+		t.Errorf("Expected only the failing service to be reported, got %+v", events)
+	}
+}
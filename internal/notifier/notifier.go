@@ -0,0 +1,217 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/ririnto/monit-exporter/internal/backoff"
+	"github.com/ririnto/monit-exporter/internal/monit"
+	"github.com/sirupsen/logrus"
+)
+
+// Notifier diffs consecutive Monit snapshots and posts service state changes to webhooks.
+//
+// In the JSON format (optionally rendered through Template) only new events are sent, and
+// an event repeating the kind last delivered for the same service within DedupWindow is suppressed.
+// In the Alertmanager format every observation re-sends the active alerts, as Alertmanager
+// expects, together with the alerts resolved since the previous snapshot.
+type Notifier struct {
+	URLs        []string
+	Format      string
+	Template    *template.Template
+	Headers     map[string]string
+	HTTPClient  *http.Client
+	Backoff     backoff.Backoff
+	MaxRetries  int
+	DedupWindow time.Duration
+
+	mutex    sync.Mutex
+	previous *monit.Monit
+	sent     map[string]delivery
+	active   map[string]alert
+}
+
+// ValidateFormat checks that format is a supported payload format.
+func ValidateFormat(format string) error {
+	if format != FormatJSON && format != FormatAlertmanager {
+		return fmt.Errorf("%w: '%s' (expected %s or %s)", ErrInvalidFormat, format, FormatJSON, FormatAlertmanager)
+	}
+	return nil
+}
+
+// Observe diffs status against the previous snapshot and delivers the resulting notifications.
+// The snapshot, dedup entries and active alerts only advance once at least one webhook
+// accepted the payload; otherwise the events are derived again on the next observation.
+func (n *Notifier) Observe(ctx context.Context, status monit.Monit) error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	now := time.Now()
+	events := Diff(n.previous, status, now)
+	for event := range slices.Values(events) {
+		logrus.Infof("Notifier.Observe: %s", event.Message)
+	}
+
+	var body []byte
+	var commit func()
+	var err error
+	switch n.Format {
+	case FormatJSON:
+		events = n.dedup(events, now)
+		commit = func() { n.record(events, now) }
+		if len(events) == 0 {
+			n.previous = &status
+			return nil
+		}
+		body, err = renderPayload(n.Template, Payload{Host: status.Server.Localhostname, Events: events})
+	case FormatAlertmanager:
+		active, alerts := n.updateAlerts(status, events, now)
+		commit = func() { n.active = active }
+		if len(alerts) == 0 {
+			n.previous = &status
+			commit()
+			return nil
+		}
+		body, err = json.Marshal(alerts)
+	default:
+		return ValidateFormat(n.Format)
+	}
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	delivered := false
+	for url := range slices.Values(n.URLs) {
+		if err := n.post(ctx, url, body); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", url, err))
+			continue
+		}
+		delivered = true
+	}
+	if delivered {
+		n.previous = &status
+		commit()
+	} else {
+		logrus.Warnf("Notifier.Observe: no webhook accepted %d events, retrying them on the next observation", len(events))
+	}
+	return errors.Join(errs...)
+}
+
+// delivery is the last event kind sent for a service and when it was sent.
+type delivery struct {
+	kind Kind
+	at   time.Time
+}
+
+// dedup drops events that repeat the kind last sent for a service within the dedup window.
+// Only the last delivered event counts, so a failure following a recovery is always sent
+// and the receiver never ends up with a stale recovery for a failing service.
+func (n *Notifier) dedup(events []Event, now time.Time) []Event {
+	kept := events[:0]
+	for event := range slices.Values(events) {
+		if last, ok := n.sent[event.Service]; ok && last.kind == event.Kind && now.Sub(last.at) < n.DedupWindow {
+			logrus.Debugf("Notifier.dedup: suppressing repeated %s event for %s", event.Kind, event.Service)
+			continue
+		}
+		kept = append(kept, event)
+	}
+	return kept
+}
+
+// record remembers events as delivered for dedup.
+func (n *Notifier) record(events []Event, now time.Time) {
+	if n.sent == nil {
+		n.sent = map[string]delivery{}
+	}
+	for event := range slices.Values(events) {
+		n.sent[event.Service] = delivery{kind: event.Kind, at: now}
+	}
+}
+
+// updateAlerts applies events to a copy of the active alerts and returns that copy together
+// with the alerts to send: the active ones followed by those resolved by this snapshot.
+func (n *Notifier) updateAlerts(status monit.Monit, events []Event, now time.Time) (map[string]alert, []alert) {
+	active := maps.Clone(n.active)
+	if active == nil {
+		active = map[string]alert{}
+	}
+	var resolved []alert
+	resolve := func(key string) {
+		if a, ok := active[key]; ok {
+			a.EndsAt = &now
+			resolved = append(resolved, a)
+			delete(active, key)
+		}
+	}
+
+	for event := range slices.Values(events) {
+		if name, ok := alertNames[event.Previous]; ok {
+			resolve(name + "/" + event.Service)
+		}
+		if name, ok := alertNames[event.Current]; ok {
+			active[name+"/"+event.Service] = newAlert(event, event.Current)
+		}
+	}
+	// A disappeared service that is reported again is resolved even if it came back healthy.
+	for service := range slices.Values(status.Services) {
+		resolve(alertNames[StateAbsent] + "/" + service.Name)
+	}
+
+	return active, append(sortedAlerts(active), resolved...)
+}
+
+// post delivers body to url, retrying network errors, 5xx and 429 responses with backoff.
+func (n *Notifier) post(ctx context.Context, url string, body []byte) error {
+	for attempt := 0; ; attempt++ {
+		retry, err := n.postOnce(ctx, url, body)
+		if err == nil || !retry || n.MaxRetries <= attempt {
+			return err
+		}
+		delay := n.Backoff.Duration(attempt)
+		logrus.Warnf("Notifier.post: attempt %d to %s failed, retrying in %s: %v", attempt+1, url, delay, err)
+		if err := backoff.Sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// postOnce performs a single POST and reports whether a failure is worth retrying.
+func (n *Notifier) postOnce(ctx context.Context, url string, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("unable to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "monit-exporter")
+	for name, value := range n.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := n.HTTPClient.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, fmt.Errorf("unable to send notification: %w", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			logrus.Warnf("Notifier.postOnce: failed to close response body: %v", cerr)
+		}
+	}()
+	if resp.StatusCode/100 == 2 {
+		return false, nil
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	retry := resp.StatusCode/100 == 5 || resp.StatusCode == http.StatusTooManyRequests
+	return retry, fmt.Errorf("webhook returned %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+}
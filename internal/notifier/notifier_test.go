@@ -0,0 +1,233 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ririnto/monit-exporter/internal/backoff"
+	"github.com/ririnto/monit-exporter/internal/monit"
+)
+
+// webhook is a webhook receiver stub recording request bodies.
+type webhook struct {
+	mutex    sync.Mutex
+	statuses []int
+	bodies   []string
+}
+
+func (h *webhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	body, _ := io.ReadAll(r.Body)
+	h.bodies = append(h.bodies, string(body))
+	status := http.StatusOK
+	if 0 < len(h.statuses) {
+		status, h.statuses = h.statuses[0], h.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+func newTestNotifier(url, format string) *Notifier {
+	return &Notifier{
+		URLs:        []string{url},
+		Format:      format,
+		HTTPClient:  http.DefaultClient,
+		Backoff:     backoff.Backoff{Min: time.Millisecond, Max: time.Millisecond},
+		MaxRetries:  2,
+		DedupWindow: time.Hour,
+	}
+}
+
+func TestNotifier_JSONWithDedup(t *testing.T) {
+	hook := &webhook{}
+	server := httptest.NewServer(hook)
+	defer server.Close()
+	n := newTestNotifier(server.URL, FormatJSON)
+	ctx := context.Background()
+
+	for _, status := range []monit.Int{0, 512, 0, 512} {
+		if err := n.Observe(ctx, snapshot(service("nginx", status, 1))); err != nil {
+			t.Fatalf("Observe returned error: %v", err)
+		}
+	}
+
+	// The first OK snapshot sends nothing; a failure after a recovery is never suppressed.
+	if len(hook.bodies) != 3 {
+		t.Fatalf("Expected 3 notifications, got %d: %v", len(hook.bodies), hook.bodies)
+	}
+	var payload Payload
+	if err := json.Unmarshal([]byte(hook.bodies[0]), &payload); err != nil {
+		t.Fatalf("Invalid JSON payload: %v", err)
+	}
+	if payload.Host != "web-1" || len(payload.Events) != 1 || payload.Events[0].Kind != KindFailed {
+		t.Errorf("Unexpected payload %+v", payload)
+	}
+	if !strings.Contains(hook.bodies[1], `"kind":"recovered"`) {
+		t.Errorf("Expected a recovered event, got %s", hook.bodies[1])
+	}
+	if !strings.Contains(hook.bodies[2], `"kind":"failed"`) {
+		t.Errorf("Expected the final notification to be failed, got %s", hook.bodies[2])
+	}
+
+	// A service that keeps disappearing repeats the disappeared event, which is suppressed.
+	for _, status := range []monit.Monit{snapshot(), snapshot(service("nginx", 0, 1)), snapshot()} {
+		if err := n.Observe(ctx, status); err != nil {
+			t.Fatalf("Observe returned error: %v", err)
+		}
+	}
+	if len(hook.bodies) != 4 || !strings.Contains(hook.bodies[3], `"kind":"disappeared"`) {
+		t.Errorf("Expected a single disappeared notification, got %v", hook.bodies[3:])
+	}
+}
+
+func TestNotifier_Alertmanager(t *testing.T) {
+	hook := &webhook{}
+	server := httptest.NewServer(hook)
+	defer server.Close()
+	n := newTestNotifier(server.URL, FormatAlertmanager)
+	ctx := context.Background()
+
+	for _, status := range []monit.Int{512, 512, 0} {
+		if err := n.Observe(ctx, snapshot(service("nginx", status, 1))); err != nil {
+			t.Fatalf("Observe returned error: %v", err)
+		}
+	}
+	if err := n.Observe(ctx, snapshot(service("nginx", 0, 1))); err != nil {
+		t.Fatalf("Observe returned error: %v", err)
+	}
+
+	// Active alerts are repeated until resolved; nothing is sent once all alerts are resolved.
+	if len(hook.bodies) != 3 {
+		t.Fatalf("Expected 3 requests, got %d: %v", len(hook.bodies), hook.bodies)
+	}
+	var firing, resolved []alert
+	if err := json.Unmarshal([]byte(hook.bodies[1]), &firing); err != nil {
+		t.Fatalf("Invalid alert payload: %v", err)
+	}
+	if len(firing) != 1 || firing[0].Labels["alertname"] != "MonitServiceFailed" || firing[0].EndsAt != nil {
+		t.Errorf("Expected one firing MonitServiceFailed alert, got %+v", firing)
+	}
+	if firing[0].Labels["severity"] != "critical" || firing[0].Labels["instance"] != "web-1" {
+		t.Errorf("Unexpected alert labels %v", firing[0].Labels)
+	}
+	if err := json.Unmarshal([]byte(hook.bodies[2]), &resolved); err != nil {
+		t.Fatalf("Invalid alert payload: %v", err)
+	}
+	if len(resolved) != 1 || resolved[0].EndsAt == nil || !resolved[0].StartsAt.Equal(firing[0].StartsAt) {
+		t.Errorf("Expected the alert to be resolved, got %+v", resolved)
+	}
+}
+
+func TestNotifier_Retries(t *testing.T) {
+	hook := &webhook{statuses: []int{http.StatusServiceUnavailable, http.StatusBadGateway}}
+	server := httptest.NewServer(hook)
+	defer server.Close()
+	n := newTestNotifier(server.URL, FormatJSON)
+
+	if err := n.Observe(context.Background(), snapshot(service("nginx", 512, 1))); err != nil {
+		t.Fatalf("Observe returned error: %v", err)
+	}
+	if len(hook.bodies) != 3 {
+		t.Errorf("Expected 3 attempts, got %d", len(hook.bodies))
+	}
+}
+
+func TestNotifier_KeepsUndeliveredEvents(t *testing.T) {
+	for _, format := range []string{FormatJSON, FormatAlertmanager} {
+		t.Run(format, func(t *testing.T) {
+			hook := &webhook{statuses: []int{500, 500, 500}}
+			server := httptest.NewServer(hook)
+			defer server.Close()
+			n := newTestNotifier(server.URL, format)
+			ctx := context.Background()
+
+			if err := n.Observe(ctx, snapshot(service("nginx", 0, 1))); err != nil {
+				t.Fatalf("Observe returned error: %v", err)
+			}
+			if err := n.Observe(ctx, snapshot(service("nginx", 512, 1))); err == nil {
+				t.Fatal("Expected an error once the retries are exhausted, got nil")
+			}
+			if len(hook.bodies) != 3 {
+				t.Fatalf("Expected 3 failed attempts, got %d", len(hook.bodies))
+			}
+
+			// The webhook recovered: the failure is delivered on the next observation.
+			if err := n.Observe(ctx, snapshot(service("nginx", 512, 1))); err != nil {
+				t.Fatalf("Observe returned error: %v", err)
+			}
+			if len(hook.bodies) != 4 {
+				t.Fatalf("Expected the pending notification to be sent, got %d requests", len(hook.bodies))
+			}
+			if format == FormatJSON && !strings.Contains(hook.bodies[3], `"kind":"failed"`) {
+				t.Errorf("Expected a failed event, got %s", hook.bodies[3])
+			}
+			if format == FormatAlertmanager && !strings.Contains(hook.bodies[3], "MonitServiceFailed") {
+				t.Errorf("Expected a MonitServiceFailed alert, got %s", hook.bodies[3])
+			}
+
+			// Once delivered, the JSON event is neither derived nor sent again.
+			if err := n.Observe(ctx, snapshot(service("nginx", 512, 1))); err != nil {
+				t.Fatalf("Observe returned error: %v", err)
+			}
+			if format == FormatJSON && len(hook.bodies) != 4 {
+				t.Errorf("Expected no further notification, got %v", hook.bodies[4:])
+			}
+		})
+	}
+}
+
+func TestNotifier_NoRetryOnClientError(t *testing.T) {
+	hook := &webhook{statuses: []int{http.StatusBadRequest}}
+	server := httptest.NewServer(hook)
+	defer server.Close()
+	n := newTestNotifier(server.URL, FormatJSON)
+
+	if err := n.Observe(context.Background(), snapshot(service("nginx", 512, 1))); err == nil {
+		t.Error("Expected an error for a rejected notification, got nil")
+	}
+	if len(hook.bodies) != 1 {
+		t.Errorf("Expected a single attempt, got %d", len(hook.bodies))
+	}
+}
+
+func TestNotifier_Template(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "slack.tmpl")
+	text := `{"text": {{range .Events}}{{json .Message}}{{end}}}`
+	if err := os.WriteFile(path, []byte(text), 0o600); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+	tmpl, err := LoadTemplate(path)
+	if err != nil {
+		t.Fatalf("LoadTemplate returned error: %v", err)
+	}
+
+	hook := &webhook{}
+	server := httptest.NewServer(hook)
+	defer server.Close()
+	n := newTestNotifier(server.URL, FormatJSON)
+	n.Template = tmpl
+
+	if err := n.Observe(context.Background(), snapshot(service("nginx", 0, 0))); err != nil {
+		t.Fatalf("Observe returned error: %v", err)
+	}
+	expected := `{"text": "Process nginx on web-1 is no longer monitored"}`
+	if len(hook.bodies) != 1 || hook.bodies[0] != expected {
+		t.Errorf("Expected %s, got %v", expected, hook.bodies)
+	}
+}
+
+func TestValidateFormat(t *testing.T) {
+	if err := ValidateFormat("xml"); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("Expected ErrInvalidFormat, got %v", err)
+	}
+}
@@ -0,0 +1,104 @@
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"text/template"
+	"time"
+)
+
+// Payload formats.
+const (
+	FormatJSON         = "json"
+	FormatAlertmanager = "alertmanager"
+)
+
+// ErrInvalidFormat is returned for an unsupported payload format.
+var ErrInvalidFormat = errors.New("invalid notification format")
+
+// Payload is the data posted in the JSON format and passed to custom templates.
+type Payload struct {
+	Host   string  `json:"host"`
+	Events []Event `json:"events"`
+}
+
+// LoadTemplate parses a custom payload template file. Templates are executed with a
+// Payload and may use the "json" function to embed values as JSON.
+func LoadTemplate(path string) (*template.Template, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read notification template: %w", err)
+	}
+	tmpl, err := template.New("payload").Funcs(template.FuncMap{
+		"json": func(v any) (string, error) {
+			b, err := json.Marshal(v)
+			return string(b), err
+		},
+	}).Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid notification template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// renderPayload encodes events as JSON, or through tmpl when one is given.
+func renderPayload(tmpl *template.Template, payload Payload) ([]byte, error) {
+	if tmpl == nil {
+		return json.Marshal(payload)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, payload); err != nil {
+		return nil, fmt.Errorf("unable to render notification template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// alert is an alert in the Alertmanager v2 API format.
+type alert struct {
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       *time.Time        `json:"endsAt,omitempty"`
+	GeneratorURL string            `json:"generatorURL,omitempty"`
+}
+
+// alertNames maps the states that raise alerts to Alertmanager alert names.
+var alertNames = map[State]string{
+	StateFailed:      "MonitServiceFailed",
+	StateUnmonitored: "MonitServiceUnmonitored",
+	StateAbsent:      "MonitServiceDisappeared",
+}
+
+// newAlert builds the alert raised by a service entering state.
+func newAlert(event Event, state State) alert {
+	severity := "warning"
+	if state == StateFailed {
+		severity = "critical"
+	}
+	return alert{
+		Labels: map[string]string{
+			"alertname":    alertNames[state],
+			"service_name": event.Service,
+			"service_type": event.ServiceType,
+			"instance":     event.Host,
+			"severity":     severity,
+		},
+		Annotations: map[string]string{"summary": event.Message},
+		StartsAt:    event.Time,
+	}
+}
+
+// sortedAlerts returns the alerts ordered by name and service for stable payloads.
+func sortedAlerts(alerts map[string]alert) []alert {
+	keys := slices.Sorted(maps.Keys(alerts))
+	result := make([]alert, 0, len(keys))
+	for key := range slices.Values(keys) {
+		result = append(result, alerts[key])
+	}
+	return result
+}
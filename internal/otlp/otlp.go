@@ -0,0 +1,219 @@
+package otlp
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/ririnto/monit-exporter/internal/backoff"
+	"github.com/ririnto/monit-exporter/internal/exporter"
+	"github.com/ririnto/monit-exporter/internal/monit"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"google.golang.org/grpc/credentials"
+)
+
+// scopeName is the instrumentation scope of the exported metrics.
+const scopeName = "github.com/ririnto/monit-exporter"
+
+// Supported OTLP transport protocols.
+const (
+	ProtocolGRPC = "grpc"
+	ProtocolHTTP = "http"
+)
+
+// ErrInvalidProtocol is returned for an unsupported OTLP protocol.
+var ErrInvalidProtocol = errors.New("invalid OTLP protocol")
+
+// Options configures the OTLP metrics export.
+type Options struct {
+	// Endpoint is host:port, or a full URL including the scheme (and path for HTTP).
+	Endpoint  string
+	Protocol  string
+	Insecure  bool
+	Headers   map[string]string
+	TLSConfig *tls.Config
+	Timeout   time.Duration
+}
+
+// NewMetricExporter creates an OTLP metric exporter for the configured protocol.
+func NewMetricExporter(ctx context.Context, opts Options) (sdkmetric.Exporter, error) {
+	withURL := strings.Contains(opts.Endpoint, "://")
+	switch opts.Protocol {
+	case ProtocolGRPC:
+		options := []otlpmetricgrpc.Option{otlpmetricgrpc.WithHeaders(opts.Headers), otlpmetricgrpc.WithTimeout(opts.Timeout)}
+		if withURL {
+			options = append(options, otlpmetricgrpc.WithEndpointURL(opts.Endpoint))
+		} else {
+			options = append(options, otlpmetricgrpc.WithEndpoint(opts.Endpoint))
+		}
+		if opts.Insecure {
+			options = append(options, otlpmetricgrpc.WithInsecure())
+		} else if opts.TLSConfig != nil {
+			options = append(options, otlpmetricgrpc.WithTLSCredentials(credentials.NewTLS(opts.TLSConfig)))
+		}
+		return otlpmetricgrpc.New(ctx, options...)
+	case ProtocolHTTP:
+		options := []otlpmetrichttp.Option{otlpmetrichttp.WithHeaders(opts.Headers), otlpmetrichttp.WithTimeout(opts.Timeout)}
+		if withURL {
+			options = append(options, otlpmetrichttp.WithEndpointURL(opts.Endpoint))
+		} else {
+			options = append(options, otlpmetrichttp.WithEndpoint(opts.Endpoint))
+		}
+		if opts.Insecure {
+			options = append(options, otlpmetrichttp.WithInsecure())
+		} else if opts.TLSConfig != nil {
+			options = append(options, otlpmetrichttp.WithTLSClientConfig(opts.TLSConfig))
+		}
+		return otlpmetrichttp.New(ctx, options...)
+	default:
+		return nil, fmt.Errorf("%w: '%s' (expected %s or %s)", ErrInvalidProtocol, opts.Protocol, ProtocolGRPC, ProtocolHTTP)
+	}
+}
+
+// Resource describes the Monit instance a snapshot was taken from.
+func Resource(status monit.Monit) *resource.Resource {
+	attrs := []attribute.KeyValue{semconv.ServiceName("monit")}
+	if status.Server.Version != "" {
+		attrs = append(attrs, semconv.ServiceVersion(status.Server.Version))
+	}
+	if status.Server.ID != "" {
+		attrs = append(attrs, semconv.ServiceInstanceID(status.Server.ID))
+	}
+	if status.Server.Localhostname != "" {
+		attrs = append(attrs, semconv.HostName(status.Server.Localhostname))
+	}
+	if status.Platform.Name != "" {
+		attrs = append(attrs, semconv.OSTypeKey.String(strings.ToLower(status.Platform.Name)))
+	}
+	if status.Platform.Release != "" {
+		attrs = append(attrs, semconv.OSVersion(status.Platform.Release))
+	}
+	return resource.NewWithAttributes(semconv.SchemaURL, attrs...)
+}
+
+// unit returns the UCUM unit implied by a Prometheus metric name suffix.
+func unit(name string) string {
+	switch {
+	case strings.HasSuffix(name, "_kilobytes"):
+		return "kBy"
+	case strings.HasSuffix(name, "_bytes"):
+		return "By"
+	case strings.HasSuffix(name, "_seconds"):
+		return "s"
+	case strings.HasSuffix(name, "_percent"):
+		return "%"
+	default:
+		return ""
+	}
+}
+
+// RegisterInstruments creates an observable gauge or, for counters, an observable counter on
+// meter for every descriptor and a callback observing the values gathered from gatherer at
+// each collection.
+func RegisterInstruments(meter metric.Meter, gatherer prometheus.Gatherer, descriptors []exporter.Descriptor) (metric.Registration, error) {
+	gauges := make(map[string]metric.Float64ObservableGauge, len(descriptors))
+	counters := make(map[string]metric.Float64ObservableCounter)
+	instruments := make([]metric.Observable, 0, len(descriptors))
+	for descriptor := range slices.Values(descriptors) {
+		description, withUnit := metric.WithDescription(descriptor.Help), metric.WithUnit(unit(descriptor.Name))
+		var instrument metric.Observable
+		var err error
+		if descriptor.Type == prometheus.CounterValue {
+			var counter metric.Float64ObservableCounter
+			counter, err = meter.Float64ObservableCounter(descriptor.Name, description, withUnit)
+			counters[descriptor.Name], instrument = counter, counter
+		} else {
+			var gauge metric.Float64ObservableGauge
+			gauge, err = meter.Float64ObservableGauge(descriptor.Name, description, withUnit)
+			gauges[descriptor.Name], instrument = gauge, gauge
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to create instrument %s: %w", descriptor.Name, err)
+		}
+		instruments = append(instruments, instrument)
+	}
+
+	return meter.RegisterCallback(func(_ context.Context, observer metric.Observer) error {
+		families, err := gatherer.Gather()
+		if err != nil {
+			return fmt.Errorf("unable to gather metrics: %w", err)
+		}
+		for family := range slices.Values(families) {
+			gauge, isGauge := gauges[family.GetName()]
+			counter, isCounter := counters[family.GetName()]
+			if !isGauge && !isCounter {
+				logrus.Debugf("RegisterInstruments: no instrument for %s", family.GetName())
+				continue
+			}
+			for m := range slices.Values(family.GetMetric()) {
+				attrs := make([]attribute.KeyValue, 0, len(m.GetLabel()))
+				for pair := range slices.Values(m.GetLabel()) {
+					attrs = append(attrs, attribute.String(pair.GetName(), pair.GetValue()))
+				}
+				if isCounter {
+					observer.ObserveFloat64(counter, m.GetCounter().GetValue(), metric.WithAttributes(attrs...))
+				} else {
+					observer.ObserveFloat64(gauge, m.GetGauge().GetValue(), metric.WithAttributes(attrs...))
+				}
+			}
+		}
+		return nil
+	}, instruments...)
+}
+
+// resourceBackoff spaces the Monit fetches made to resolve the resource attributes.
+var resourceBackoff = backoff.Backoff{Min: time.Second, Max: 15 * time.Second, Jitter: true}
+
+// fetchResourceStatus fetches the Monit status until it succeeds or ctx is done.
+func fetchResourceStatus(ctx context.Context, exp *exporter.Exporter) (monit.Monit, error) {
+	for attempt := 0; ; attempt++ {
+		status, err := exp.FetchContext(ctx)
+		if err == nil {
+			return status, nil
+		}
+		delay := resourceBackoff.Duration(attempt)
+		logrus.Warnf("fetchResourceStatus: unable to fetch Monit status for resource attributes, retrying in %s: %v", delay, err)
+		if sleepErr := backoff.Sleep(ctx, delay); sleepErr != nil {
+			return monit.Monit{}, fmt.Errorf("unable to fetch Monit status for resource attributes: %w", err)
+		}
+	}
+}
+
+// NewMeterProvider creates a meter provider whose reader collects the Exporter's metrics.
+// The resource is taken from Monit, which is fetched with backoff until it answers;
+// an error is returned if ctx is done first. Callers that must not wait for Monit run it
+// in the background.
+func NewMeterProvider(ctx context.Context, exp *exporter.Exporter, reader sdkmetric.Reader) (*sdkmetric.MeterProvider, error) {
+	status, err := fetchResourceStatus(ctx, exp)
+	if err != nil {
+		_ = reader.Shutdown(context.Background())
+		return nil, err
+	}
+
+	registry := prometheus.NewRegistry()
+	if err := registry.Register(exp); err != nil {
+		return nil, fmt.Errorf("failed to register exporter: %w", err)
+	}
+
+	provider := sdkmetric.NewMeterProvider(
+		sdkmetric.WithResource(Resource(status)),
+		sdkmetric.WithReader(reader),
+	)
+	if _, err := RegisterInstruments(provider.Meter(scopeName), registry, exp.Descriptors()); err != nil {
+		_ = provider.Shutdown(context.Background())
+		return nil, err
+	}
+	return provider, nil
+}
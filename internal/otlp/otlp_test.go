@@ -0,0 +1,269 @@
+package otlp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ririnto/monit-exporter/internal/backoff"
+	"github.com/ririnto/monit-exporter/internal/config"
+	"github.com/ririnto/monit-exporter/internal/exporter"
+	"github.com/ririnto/monit-exporter/internal/monit"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	collectormetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+const testXML = `<?xml version="1.0"?>
+<monit>
+  <server><id>abc123</id><version>5.33.0</version><localhostname>web-1</localhostname></server>
+  <platform><name>Linux</name><release>6.1.0</release></platform>
+  <service type="0"><name>rootfs</name><status>0</status><monitor>1</monitor><block><percent>42.5</percent></block></service>
+</monit>`
+
+func newTestExporter(t *testing.T) *exporter.Exporter {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "status.xml")
+	if err := os.WriteFile(path, []byte(testXML), 0o600); err != nil {
+		t.Fatalf("Failed to write fixture: %v", err)
+	}
+	exp, err := exporter.NewExporter(&config.Config{MonitScrapeURI: "file://" + path})
+	if err != nil {
+		t.Fatalf("NewExporter returned error: %v", err)
+	}
+	return exp
+}
+
+func TestResource(t *testing.T) {
+	status, err := monit.ParseMonitStatus([]byte(testXML))
+	if err != nil {
+		t.Fatalf("ParseMonitStatus returned error: %v", err)
+	}
+
+	res := Resource(status)
+	for key, expected := range map[attribute.Key]string{
+		semconv.HostNameKey:          "web-1",
+		semconv.OSTypeKey:            "linux",
+		semconv.ServiceInstanceIDKey: "abc123",
+		semconv.ServiceNameKey:       "monit",
+	} {
+		value, ok := res.Set().Value(key)
+		if !ok || value.AsString() != expected {
+			t.Errorf("Expected %s=%s, got %s", key, expected, value.AsString())
+		}
+	}
+}
+
+func TestNewMeterProvider(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider, err := NewMeterProvider(context.Background(), newTestExporter(t), reader)
+	if err != nil {
+		t.Fatalf("NewMeterProvider returned error: %v", err)
+	}
+	defer func() { _ = provider.Shutdown(context.Background()) }()
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect returned error: %v", err)
+	}
+	if value, _ := rm.Resource.Set().Value(semconv.HostNameKey); value.AsString() != "web-1" {
+		t.Errorf("Expected host.name web-1, got %s", value.AsString())
+	}
+
+	found := map[string]metricdata.Metrics{}
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			found[m.Name] = m
+		}
+	}
+	block, ok := found["monit_service_block_usage_percent"]
+	if !ok {
+		t.Fatalf("Expected monit_service_block_usage_percent, got %v", found)
+	}
+	if block.Unit != "%" {
+		t.Errorf("Expected unit %%, got %s", block.Unit)
+	}
+	points := block.Data.(metricdata.Gauge[float64]).DataPoints
+	if len(points) != 1 || points[0].Value != 42.5 {
+		t.Fatalf("Expected one point with value 42.5, got %+v", points)
+	}
+	if name, _ := points[0].Attributes.Value("service_name"); name.AsString() != "rootfs" {
+		t.Errorf("Expected service_name rootfs, got %s", name.AsString())
+	}
+	if up := found["monit_exporter_up"].Data.(metricdata.Gauge[float64]).DataPoints; len(up) != 1 || up[0].Value != 1 {
+		t.Errorf("Expected monit_exporter_up 1, got %+v", up)
+	}
+}
+
+func TestNewMetricExporter_HTTP(t *testing.T) {
+	received := make(chan *collectormetrics.ExportMetricsServiceRequest, 1)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		request := &collectormetrics.ExportMetricsServiceRequest{}
+		if r.URL.Path != "/v1/metrics" || proto.Unmarshal(body, request) != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		select {
+		case received <- request:
+		default:
+		}
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer collector.Close()
+
+	metricExporter, err := NewMetricExporter(context.Background(), Options{
+		Endpoint: collector.URL + "/v1/metrics",
+		Protocol: ProtocolHTTP,
+		Insecure: true,
+		Timeout:  5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("NewMetricExporter returned error: %v", err)
+	}
+	provider, err := NewMeterProvider(context.Background(), newTestExporter(t), sdkmetric.NewPeriodicReader(metricExporter))
+	if err != nil {
+		t.Fatalf("NewMeterProvider returned error: %v", err)
+	}
+	if err := provider.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("ForceFlush returned error: %v", err)
+	}
+	_ = provider.Shutdown(context.Background())
+
+	select {
+	case request := <-received:
+		attrs := request.GetResourceMetrics()[0].GetResource().GetAttributes()
+		var instanceID string
+		for _, attr := range attrs {
+			if attr.GetKey() == string(semconv.ServiceInstanceIDKey) {
+				instanceID = attr.GetValue().GetStringValue()
+			}
+		}
+		if instanceID != "abc123" {
+			t.Errorf("Expected service.instance.id abc123, got %v", attrs)
+		}
+	default:
+		t.Fatal("Expected the collector to receive an export request")
+	}
+}
+
+func TestNewMetricExporter_InvalidProtocol(t *testing.T) {
+	_, err := NewMetricExporter(context.Background(), Options{Endpoint: "localhost:4317", Protocol: "udp"})
+	if !errors.Is(err, ErrInvalidProtocol) {
+		t.Errorf("Expected ErrInvalidProtocol, got %v", err)
+	}
+}
+
+func TestNewMeterProvider_WaitsForMonit(t *testing.T) {
+	defer func(b backoff.Backoff) { resourceBackoff = b }(resourceBackoff)
+	resourceBackoff = backoff.Backoff{Min: 10 * time.Millisecond}
+
+	var requests atomic.Int32
+	monitServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = io.WriteString(w, testXML)
+	}))
+	defer monitServer.Close()
+	exp, err := exporter.NewExporter(&config.Config{MonitScrapeURI: monitServer.URL, ScrapeTimeout: time.Second})
+	if err != nil {
+		t.Fatalf("NewExporter returned error: %v", err)
+	}
+
+	reader := sdkmetric.NewManualReader()
+	provider, err := NewMeterProvider(context.Background(), exp, reader)
+	if err != nil {
+		t.Fatalf("NewMeterProvider returned error: %v", err)
+	}
+	defer func() { _ = provider.Shutdown(context.Background()) }()
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect returned error: %v", err)
+	}
+	if value, _ := rm.Resource.Set().Value(semconv.HostNameKey); value.AsString() != "web-1" {
+		t.Errorf("Expected host.name web-1, got %s", value.AsString())
+	}
+}
+
+func TestNewMeterProvider_MonitDown(t *testing.T) {
+	defer func(b backoff.Backoff) { resourceBackoff = b }(resourceBackoff)
+	resourceBackoff = backoff.Backoff{Min: 10 * time.Millisecond}
+
+	monitServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer monitServer.Close()
+	exp, err := exporter.NewExporter(&config.Config{MonitScrapeURI: monitServer.URL, ScrapeTimeout: time.Second})
+	if err != nil {
+		t.Fatalf("NewExporter returned error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if _, err := NewMeterProvider(ctx, exp, sdkmetric.NewManualReader()); err == nil {
+		t.Error("Expected an error while Monit is down, got nil")
+	}
+}
+
+func TestNewMeterProvider_RetriesCounter(t *testing.T) {
+	var requests atomic.Int32
+	monitServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = io.WriteString(w, testXML)
+	}))
+	defer monitServer.Close()
+	exp, err := exporter.NewExporter(&config.Config{
+		MonitScrapeURI:  monitServer.URL,
+		ScrapeTimeout:   time.Second,
+		MaxRetries:      2,
+		RetryMinBackoff: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewExporter returned error: %v", err)
+	}
+
+	reader := sdkmetric.NewManualReader()
+	provider, err := NewMeterProvider(context.Background(), exp, reader)
+	if err != nil {
+		t.Fatalf("NewMeterProvider returned error: %v", err)
+	}
+	defer func() { _ = provider.Shutdown(context.Background()) }()
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect returned error: %v", err)
+	}
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if m.Name != "monit_exporter_fetch_retries_total" {
+				continue
+			}
+			sum, ok := m.Data.(metricdata.Sum[float64])
+			if !ok || !sum.IsMonotonic {
+				t.Fatalf("Expected a monotonic sum, got %T", m.Data)
+			}
+			if len(sum.DataPoints) != 1 || sum.DataPoints[0].Value != 1 {
+				t.Errorf("Expected one retry, got %+v", sum.DataPoints)
+			}
+			return
+		}
+	}
+	t.Fatal("Expected monit_exporter_fetch_retries_total to be exported")
+}
@@ -0,0 +1,82 @@
+package remotewrite
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RecoverableError reports a failed send that may succeed when retried.
+type RecoverableError struct {
+	Err error
+}
+
+// Error returns the underlying error message.
+func (e *RecoverableError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *RecoverableError) Unwrap() error {
+	return e.Err
+}
+
+// Client sends snappy-compressed write requests to a remote-write endpoint.
+type Client struct {
+	URL         string
+	HTTPClient  *http.Client
+	Headers     map[string]string
+	Username    string
+	Password    string
+	BearerToken string
+}
+
+// Send posts a snappy-compressed write request. Network errors, 5xx and 429
+// responses are returned as *RecoverableError.
+func (c *Client) Send(ctx context.Context, compressed []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL, bytes.NewReader(compressed))
+	if err != nil {
+		return fmt.Errorf("unable to create request: %w", err)
+	}
+	for name, value := range c.Headers {
+		req.Header.Set(name, value)
+	}
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("User-Agent", "monit-exporter")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	switch {
+	case c.BearerToken != "":
+		req.Header.Set("Authorization", "Bearer "+c.BearerToken)
+	case c.Username != "":
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.Canceled) {
+			return err
+		}
+		return &RecoverableError{Err: fmt.Errorf("unable to send write request: %w", err)}
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			logrus.Warnf("Client.Send: failed to close response body: %v", cerr)
+		}
+	}()
+
+	if resp.StatusCode/100 == 2 {
+		return nil
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	err = fmt.Errorf("remote write endpoint returned %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+	if resp.StatusCode/100 == 5 || resp.StatusCode == http.StatusTooManyRequests {
+		return &RecoverableError{Err: err}
+	}
+	return err
+}
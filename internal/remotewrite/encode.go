@@ -0,0 +1,202 @@
+package remotewrite
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Label is a name/value pair identifying a time series.
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Sample is a single value of a time series at a millisecond timestamp.
+type Sample struct {
+	Value     float64
+	Timestamp int64
+}
+
+// TimeSeries is a labelled series of samples as sent in a remote-write request.
+type TimeSeries struct {
+	Labels  []Label
+	Samples []Sample
+}
+
+// FromMetricFamilies converts gathered metric families to remote-write time series.
+// External labels are added to every series that does not already define them, and
+// samples without an explicit timestamp are stamped with now. Only counter, gauge and
+// untyped metrics are supported; other types are skipped.
+func FromMetricFamilies(families []*dto.MetricFamily, external []Label, now time.Time) []TimeSeries {
+	var series []TimeSeries
+	for family := range slices.Values(families) {
+		for metric := range slices.Values(family.GetMetric()) {
+			var value float64
+			switch family.GetType() {
+			case dto.MetricType_GAUGE:
+				value = metric.GetGauge().GetValue()
+			case dto.MetricType_COUNTER:
+				value = metric.GetCounter().GetValue()
+			case dto.MetricType_UNTYPED:
+				value = metric.GetUntyped().GetValue()
+			default:
+				logrus.Debugf("FromMetricFamilies: skipping %s of unsupported type %s", family.GetName(), family.GetType())
+				continue
+			}
+
+			labels := []Label{{Name: "__name__", Value: family.GetName()}}
+			for pair := range slices.Values(metric.GetLabel()) {
+				labels = append(labels, Label{Name: pair.GetName(), Value: pair.GetValue()})
+			}
+			for label := range slices.Values(external) {
+				if !slices.ContainsFunc(labels, func(l Label) bool { return l.Name == label.Name }) {
+					labels = append(labels, label)
+				}
+			}
+			slices.SortFunc(labels, func(a, b Label) int { return strings.Compare(a.Name, b.Name) })
+
+			timestamp := now.UnixMilli()
+			if metric.TimestampMs != nil {
+				timestamp = metric.GetTimestampMs()
+			}
+			series = append(series, TimeSeries{
+				Labels:  labels,
+				Samples: []Sample{{Value: value, Timestamp: timestamp}},
+			})
+		}
+	}
+	return series
+}
+
+// MarshalWriteRequest encodes series as a prometheus.WriteRequest protobuf message.
+func MarshalWriteRequest(series []TimeSeries) []byte {
+	var b []byte
+	for ts := range slices.Values(series) {
+		var tsb []byte
+		for label := range slices.Values(ts.Labels) {
+			var lb []byte
+			lb = protowire.AppendTag(lb, 1, protowire.BytesType)
+			lb = protowire.AppendString(lb, label.Name)
+			lb = protowire.AppendTag(lb, 2, protowire.BytesType)
+			lb = protowire.AppendString(lb, label.Value)
+			tsb = protowire.AppendTag(tsb, 1, protowire.BytesType)
+			tsb = protowire.AppendBytes(tsb, lb)
+		}
+		for sample := range slices.Values(ts.Samples) {
+			var sb []byte
+			sb = protowire.AppendTag(sb, 1, protowire.Fixed64Type)
+			sb = protowire.AppendFixed64(sb, math.Float64bits(sample.Value))
+			sb = protowire.AppendTag(sb, 2, protowire.VarintType)
+			sb = protowire.AppendVarint(sb, uint64(sample.Timestamp))
+			tsb = protowire.AppendTag(tsb, 2, protowire.BytesType)
+			tsb = protowire.AppendBytes(tsb, sb)
+		}
+		b = protowire.AppendTag(b, 1, protowire.BytesType)
+		b = protowire.AppendBytes(b, tsb)
+	}
+	return b
+}
+
+// UnmarshalWriteRequest decodes a prometheus.WriteRequest protobuf message, as used
+// by receivers and tests. Unknown fields are skipped.
+func UnmarshalWriteRequest(b []byte) ([]TimeSeries, error) {
+	var series []TimeSeries
+	err := walkMessage(b, func(num protowire.Number, v []byte) error {
+		if num != 1 {
+			return nil
+		}
+		var ts TimeSeries
+		err := walkMessage(v, func(num protowire.Number, v []byte) error {
+			switch num {
+			case 1:
+				var label Label
+				err := walkMessage(v, func(num protowire.Number, v []byte) error {
+					switch num {
+					case 1:
+						label.Name = string(v)
+					case 2:
+						label.Value = string(v)
+					}
+					return nil
+				})
+				ts.Labels = append(ts.Labels, label)
+				return err
+			case 2:
+				sample, err := unmarshalSample(v)
+				ts.Samples = append(ts.Samples, sample)
+				return err
+			}
+			return nil
+		})
+		series = append(series, ts)
+		return err
+	})
+	return series, err
+}
+
+// walkMessage calls fn for every length-delimited field of a message and skips the rest.
+func walkMessage(b []byte, fn func(protowire.Number, []byte) error) error {
+	for 0 < len(b) {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		if typ != protowire.BytesType {
+			if n = protowire.ConsumeFieldValue(num, typ, b); n < 0 {
+				return protowire.ParseError(n)
+			}
+			b = b[n:]
+			continue
+		}
+		v, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		if err := fn(num, v); err != nil {
+			return err
+		}
+		b = b[n:]
+	}
+	return nil
+}
+
+// unmarshalSample decodes a prometheus.Sample message.
+func unmarshalSample(b []byte) (Sample, error) {
+	var sample Sample
+	for 0 < len(b) {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return sample, protowire.ParseError(n)
+		}
+		b = b[n:]
+		switch {
+		case num == 1 && typ == protowire.Fixed64Type:
+			v, n := protowire.ConsumeFixed64(b)
+			if n < 0 {
+				return sample, protowire.ParseError(n)
+			}
+			sample.Value = math.Float64frombits(v)
+			b = b[n:]
+		case num == 2 && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
+			if n < 0 {
+				return sample, protowire.ParseError(n)
+			}
+			sample.Timestamp = int64(v)
+			b = b[n:]
+		default:
+			if n = protowire.ConsumeFieldValue(num, typ, b); n < 0 {
+				return sample, protowire.ParseError(n)
+			}
+			b = b[n:]
+		}
+	}
+	return sample, nil
+}
@@ -0,0 +1,64 @@
+package remotewrite
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestFromMetricFamilies(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	gauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "monit_test", Help: "test"}, []string{"service_name", "job"})
+	registry.MustRegister(gauge)
+	gauge.WithLabelValues("nginx", "custom").Set(1.5)
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather returned error: %v", err)
+	}
+	now := time.UnixMilli(1700000000000)
+	series := FromMetricFamilies(families, []Label{{Name: "job", Value: "monit"}, {Name: "site", Value: "edge"}}, now)
+
+	expected := []TimeSeries{{
+		Labels: []Label{
+			{Name: "__name__", Value: "monit_test"},
+			{Name: "job", Value: "custom"},
+			{Name: "service_name", Value: "nginx"},
+			{Name: "site", Value: "edge"},
+		},
+		Samples: []Sample{{Value: 1.5, Timestamp: 1700000000000}},
+	}}
+	if !reflect.DeepEqual(series, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, series)
+	}
+}
+
+func TestMarshalWriteRequest_RoundTrip(t *testing.T) {
+	series := []TimeSeries{
+		{
+			Labels:  []Label{{Name: "__name__", Value: "a"}, {Name: "x", Value: "1"}},
+			Samples: []Sample{{Value: -2.25, Timestamp: 42}},
+		},
+		{
+			Labels:  []Label{{Name: "__name__", Value: "b"}},
+			Samples: []Sample{{Value: 0, Timestamp: 1700000000000}},
+		},
+	}
+
+	decoded, err := UnmarshalWriteRequest(MarshalWriteRequest(series))
+	if err != nil {
+		t.Fatalf("UnmarshalWriteRequest returned error: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, series) {
+		t.Errorf("Expected %+v, got %+v", series, decoded)
+	}
+}
+
+func TestUnmarshalWriteRequest_Truncated(t *testing.T) {
+	data := MarshalWriteRequest([]TimeSeries{{Labels: []Label{{Name: "__name__", Value: "a"}}}})
+	if _, err := UnmarshalWriteRequest(data[:len(data)-1]); err == nil {
+		t.Error("Expected an error for a truncated message, got nil")
+	}
+}
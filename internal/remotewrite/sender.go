@@ -0,0 +1,92 @@
+package remotewrite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/ririnto/monit-exporter/internal/backoff"
+	"github.com/sirupsen/logrus"
+)
+
+// Sender gathers metrics, buffers the encoded write requests in a WAL and
+// delivers them to a remote-write endpoint in order.
+type Sender struct {
+	Gatherer       prometheus.Gatherer
+	Client         *Client
+	WAL            *WAL
+	ExternalLabels []Label
+	Backoff        backoff.Backoff
+	MaxRetries     int
+}
+
+// Collect gathers the metrics once and appends the compressed write request to the WAL.
+func (s *Sender) Collect(now time.Time) error {
+	families, err := s.Gatherer.Gather()
+	if err != nil {
+		return fmt.Errorf("unable to gather metrics: %w", err)
+	}
+	series := FromMetricFamilies(families, s.ExternalLabels, now)
+	if len(series) == 0 {
+		logrus.Debug("Sender.Collect: no series gathered")
+		return nil
+	}
+	payload := snappy.Encode(nil, MarshalWriteRequest(series))
+	logrus.Debugf("Sender.Collect: buffering %d series (%d bytes)", len(series), len(payload))
+	return s.WAL.Append(payload)
+}
+
+// Flush delivers the buffered write requests oldest first. Recoverable errors are
+// retried with backoff up to MaxRetries times; if a request still fails it is kept
+// for the next flush and delivery stops, so requests are never sent out of order.
+// Requests rejected with a non-recoverable error are dropped.
+func (s *Sender) Flush(ctx context.Context) error {
+	segments, err := s.WAL.Segments()
+	if err != nil {
+		return err
+	}
+	for i, segment := range segments {
+		payload, err := s.WAL.Read(segment)
+		if err != nil {
+			return err
+		}
+		if err := s.send(ctx, payload); err != nil {
+			var recoverable *RecoverableError
+			if errors.As(err, &recoverable) || ctx.Err() != nil {
+				return fmt.Errorf("remote write endpoint unavailable, %d requests buffered: %w", len(segments)-i, err)
+			}
+			logrus.Errorf("Sender.Flush: dropping rejected write request %s: %v", segment, err)
+		}
+		if err := s.WAL.Remove(segment); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// send delivers one payload, retrying recoverable errors with backoff.
+func (s *Sender) send(ctx context.Context, payload []byte) error {
+	for attempt := 0; ; attempt++ {
+		err := s.Client.Send(ctx, payload)
+		var recoverable *RecoverableError
+		if err == nil || !errors.As(err, &recoverable) || s.MaxRetries <= attempt {
+			return err
+		}
+		delay := s.Backoff.Duration(attempt)
+		logrus.Warnf("Sender.send: attempt %d failed, retrying in %s: %v", attempt+1, delay, err)
+		if err := backoff.Sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// Tick collects once and then flushes the WAL.
+func (s *Sender) Tick(ctx context.Context) error {
+	if err := s.Collect(time.Now()); err != nil {
+		return err
+	}
+	return s.Flush(ctx)
+}
@@ -0,0 +1,177 @@
+package remotewrite
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/ririnto/monit-exporter/internal/backoff"
+)
+
+// receiver is a remote-write endpoint stub recording decoded requests.
+type receiver struct {
+	mutex    sync.Mutex
+	status   int
+	requests [][]TimeSeries
+	headers  []http.Header
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.status != http.StatusNoContent {
+		w.WriteHeader(r.status)
+		return
+	}
+	compressed, _ := io.ReadAll(req.Body)
+	data, err := snappy.Decode(nil, compressed)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	series, err := UnmarshalWriteRequest(data)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	r.requests = append(r.requests, series)
+	r.headers = append(r.headers, req.Header.Clone())
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func newTestSender(t *testing.T, url string) (*Sender, prometheus.Gauge) {
+	t.Helper()
+	wal, err := OpenWAL(t.TempDir(), 0)
+	if err != nil {
+		t.Fatalf("OpenWAL returned error: %v", err)
+	}
+	registry := prometheus.NewRegistry()
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "monit_test", Help: "test"})
+	registry.MustRegister(gauge)
+	return &Sender{
+		Gatherer:       registry,
+		Client:         &Client{URL: url, HTTPClient: http.DefaultClient, BearerToken: "token"},
+		WAL:            wal,
+		ExternalLabels: []Label{{Name: "cluster", Value: "edge"}},
+		Backoff:        backoff.Backoff{Min: time.Millisecond, Max: time.Millisecond},
+		MaxRetries:     1,
+	}, gauge
+}
+
+func TestSender_Tick(t *testing.T) {
+	stub := &receiver{status: http.StatusNoContent}
+	server := httptest.NewServer(stub)
+	defer server.Close()
+
+	sender, gauge := newTestSender(t, server.URL)
+	gauge.Set(3)
+	if err := sender.Tick(context.Background()); err != nil {
+		t.Fatalf("Tick returned error: %v", err)
+	}
+
+	if len(stub.requests) != 1 {
+		t.Fatalf("Expected 1 request, got %d", len(stub.requests))
+	}
+	header := stub.headers[0]
+	if header.Get("Content-Encoding") != "snappy" || header.Get("X-Prometheus-Remote-Write-Version") != "0.1.0" {
+		t.Errorf("Unexpected remote-write headers %v", header)
+	}
+	if header.Get("Authorization") != "Bearer token" {
+		t.Errorf("Expected bearer token, got '%s'", header.Get("Authorization"))
+	}
+	series := stub.requests[0][0]
+	if len(series.Labels) != 2 || series.Labels[1] != (Label{Name: "cluster", Value: "edge"}) {
+		t.Errorf("Expected external label cluster=edge, got %v", series.Labels)
+	}
+	if series.Samples[0].Value != 3 {
+		t.Errorf("Expected sample value 3, got %v", series.Samples[0].Value)
+	}
+	if segments, _ := sender.WAL.Segments(); len(segments) != 0 {
+		t.Errorf("Expected empty WAL after delivery, got %d segments", len(segments))
+	}
+}
+
+func TestSender_BuffersWhileOffline(t *testing.T) {
+	stub := &receiver{status: http.StatusServiceUnavailable}
+	server := httptest.NewServer(stub)
+	defer server.Close()
+
+	sender, gauge := newTestSender(t, server.URL)
+	for value := range 2 {
+		gauge.Set(float64(value))
+		if err := sender.Tick(context.Background()); err == nil {
+			t.Fatal("Expected an error while the endpoint is unavailable, got nil")
+		}
+	}
+	if segments, _ := sender.WAL.Segments(); len(segments) != 2 {
+		t.Fatalf("Expected 2 buffered segments, got %d", len(segments))
+	}
+
+	stub.mutex.Lock()
+	stub.status = http.StatusNoContent
+	stub.mutex.Unlock()
+	if err := sender.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush returned error: %v", err)
+	}
+	if len(stub.requests) != 2 {
+		t.Fatalf("Expected 2 delivered requests, got %d", len(stub.requests))
+	}
+	for i, request := range stub.requests {
+		if request[0].Samples[0].Value != float64(i) {
+			t.Errorf("Expected request %d to carry value %d, got %v", i, i, request[0].Samples[0].Value)
+		}
+	}
+}
+
+func TestSender_FlushReportsRemainingRequests(t *testing.T) {
+	stub := &receiver{status: http.StatusServiceUnavailable}
+	var accept atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		stub.mutex.Lock()
+		stub.status = http.StatusServiceUnavailable
+		if 0 <= accept.Add(-1) {
+			stub.status = http.StatusNoContent
+		}
+		stub.mutex.Unlock()
+		stub.ServeHTTP(w, req)
+	}))
+	defer server.Close()
+
+	sender, gauge := newTestSender(t, server.URL)
+	for value := range 3 {
+		gauge.Set(float64(value))
+		_ = sender.Tick(context.Background())
+	}
+
+	// Accept the first buffered request only.
+	accept.Store(1)
+	err := sender.Flush(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "2 requests buffered") {
+		t.Errorf("Expected 2 requests to remain buffered, got %v", err)
+	}
+	if segments, _ := sender.WAL.Segments(); len(segments) != 2 {
+		t.Errorf("Expected 2 buffered segments, got %d", len(segments))
+	}
+}
+
+func TestSender_DropsRejectedRequests(t *testing.T) {
+	stub := &receiver{status: http.StatusBadRequest}
+	server := httptest.NewServer(stub)
+	defer server.Close()
+
+	sender, _ := newTestSender(t, server.URL)
+	if err := sender.Tick(context.Background()); err != nil {
+		t.Fatalf("Tick returned error: %v", err)
+	}
+	if segments, _ := sender.WAL.Segments(); len(segments) != 0 {
+		t.Errorf("Expected rejected request to be dropped, got %d segments", len(segments))
+	}
+}
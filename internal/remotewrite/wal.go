@@ -0,0 +1,127 @@
+package remotewrite
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// segmentSuffix is the file extension of WAL segments.
+const segmentSuffix = ".seg"
+
+// WAL buffers encoded write requests on disk, one segment file per request,
+// until they have been delivered. Segment names sort in append order.
+type WAL struct {
+	dir         string
+	maxSegments int
+
+	mutex sync.Mutex
+	seq   uint64
+}
+
+// OpenWAL opens (creating if needed) a WAL in dir that keeps at most maxSegments
+// pending requests; zero means unlimited.
+func OpenWAL(dir string, maxSegments int) (*WAL, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("unable to create WAL directory: %w", err)
+	}
+	w := &WAL{dir: dir, maxSegments: maxSegments}
+	segments, err := w.Segments()
+	if err != nil {
+		return nil, err
+	}
+	if 0 < len(segments) {
+		last := strings.TrimSuffix(segments[len(segments)-1], segmentSuffix)
+		if w.seq, err = strconv.ParseUint(last, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid WAL segment name '%s': %w", last, err)
+		}
+		logrus.Infof("OpenWAL: found %d pending segments in %s", len(segments), dir)
+	}
+	return w, nil
+}
+
+// Append durably stores payload as a new segment, dropping the oldest segments
+// if the WAL would exceed its maximum size.
+func (w *WAL) Append(payload []byte) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.seq++
+	name := fmt.Sprintf("%020d%s", w.seq, segmentSuffix)
+	tmp, err := os.CreateTemp(w.dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("unable to create WAL segment: %w", err)
+	}
+	if _, err := tmp.Write(payload); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("unable to write WAL segment: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("unable to sync WAL segment: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("unable to close WAL segment: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(w.dir, name)); err != nil {
+		return fmt.Errorf("unable to commit WAL segment: %w", err)
+	}
+
+	if w.maxSegments <= 0 {
+		return nil
+	}
+	segments, err := w.Segments()
+	if err != nil {
+		return err
+	}
+	for 0 < len(segments)-w.maxSegments {
+		logrus.Warnf("WAL.Append: WAL full, dropping oldest segment %s", segments[0])
+		if err := w.Remove(segments[0]); err != nil {
+			return err
+		}
+		segments = segments[1:]
+	}
+	return nil
+}
+
+// Segments returns the names of the pending segments, oldest first.
+func (w *WAL) Segments() ([]string, error) {
+	entries, err := os.ReadDir(w.dir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list WAL directory: %w", err)
+	}
+	var segments []string
+	for entry := range slices.Values(entries) {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), segmentSuffix) {
+			segments = append(segments, entry.Name())
+		}
+	}
+	slices.Sort(segments)
+	return segments, nil
+}
+
+// Read returns the payload of a segment.
+func (w *WAL) Read(name string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(w.dir, name))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read WAL segment: %w", err)
+	}
+	return data, nil
+}
+
+// Remove deletes a delivered or dropped segment.
+func (w *WAL) Remove(name string) error {
+	if err := os.Remove(filepath.Join(w.dir, name)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("unable to remove WAL segment: %w", err)
+	}
+	return nil
+}
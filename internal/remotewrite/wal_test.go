@@ -0,0 +1,70 @@
+package remotewrite
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWAL_AppendOrderAndReopen(t *testing.T) {
+	dir := t.TempDir()
+	wal, err := OpenWAL(dir, 0)
+	if err != nil {
+		t.Fatalf("OpenWAL returned error: %v", err)
+	}
+	for _, payload := range []string{"one", "two"} {
+		if err := wal.Append([]byte(payload)); err != nil {
+			t.Fatalf("Append returned error: %v", err)
+		}
+	}
+
+	reopened, err := OpenWAL(dir, 0)
+	if err != nil {
+		t.Fatalf("OpenWAL returned error: %v", err)
+	}
+	if err := reopened.Append([]byte("three")); err != nil {
+		t.Fatalf("Append returned error: %v", err)
+	}
+
+	segments, err := reopened.Segments()
+	if err != nil {
+		t.Fatalf("Segments returned error: %v", err)
+	}
+	var payloads []string
+	for _, segment := range segments {
+		data, err := reopened.Read(segment)
+		if err != nil {
+			t.Fatalf("Read returned error: %v", err)
+		}
+		payloads = append(payloads, string(data))
+	}
+	if expected := []string{"one", "two", "three"}; !reflect.DeepEqual(payloads, expected) {
+		t.Errorf("Expected %v, got %v", expected, payloads)
+	}
+}
+
+func TestWAL_MaxSegmentsDropsOldest(t *testing.T) {
+	wal, err := OpenWAL(t.TempDir(), 2)
+	if err != nil {
+		t.Fatalf("OpenWAL returned error: %v", err)
+	}
+	for _, payload := range []string{"one", "two", "three"} {
+		if err := wal.Append([]byte(payload)); err != nil {
+			t.Fatalf("Append returned error: %v", err)
+		}
+	}
+
+	segments, err := wal.Segments()
+	if err != nil {
+		t.Fatalf("Segments returned error: %v", err)
+	}
+	if len(segments) != 2 {
+		t.Fatalf("Expected 2 segments, got %d", len(segments))
+	}
+	data, err := wal.Read(segments[0])
+	if err != nil {
+		t.Fatalf("Read returned error: %v", err)
+	}
+	if string(data) != "two" {
+		t.Errorf("Expected oldest remaining payload 'two', got '%s'", data)
+	}
+}
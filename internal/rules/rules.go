@@ -0,0 +1,201 @@
+package rules
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/ririnto/monit-exporter/internal/exporter"
+)
+
+// Thresholds configures the generated alerting rules.
+type Thresholds struct {
+	// ExporterDownFor is how long Monit must be unreachable before alerting.
+	ExporterDownFor time.Duration
+	// ServiceFailingFor is how long a failure bit must be set before alerting.
+	ServiceFailingFor time.Duration
+	// For is the pending period of the capacity, certificate and staleness alerts.
+	For time.Duration
+	// FilesystemPercent is the block usage percentage considered nearly full.
+	FilesystemPercent float64
+	// InodePercent is the inode usage percentage considered nearly exhausted.
+	InodePercent float64
+	// CertificateDays is the remaining certificate validity that triggers an alert.
+	CertificateDays float64
+	// StaleAfter is the age of the last Monit collection considered stale.
+	StaleAfter time.Duration
+}
+
+// DefaultThresholds returns the thresholds used when no flag overrides them.
+func DefaultThresholds() Thresholds {
+	return Thresholds{
+		ExporterDownFor:   5 * time.Minute,
+		ServiceFailingFor: 2 * time.Minute,
+		For:               15 * time.Minute,
+		FilesystemPercent: 90,
+		InodePercent:      90,
+		CertificateDays:   14,
+		StaleAfter:        5 * time.Minute,
+	}
+}
+
+// File is a Prometheus rule file.
+type File struct {
+	Groups []Group `json:"groups"`
+}
+
+// Group is a named group of rules.
+type Group struct {
+	Name  string `json:"name"`
+	Rules []Rule `json:"rules"`
+}
+
+// Rule is a recording or alerting rule.
+type Rule struct {
+	Record      string            `json:"record,omitempty"`
+	Alert       string            `json:"alert,omitempty"`
+	Expr        string            `json:"expr"`
+	For         string            `json:"for,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// PrometheusRule is the Prometheus Operator custom resource wrapping a rule file.
+type PrometheusRule struct {
+	APIVersion string   `json:"apiVersion"`
+	Kind       string   `json:"kind"`
+	Metadata   Metadata `json:"metadata"`
+	Spec       File     `json:"spec"`
+}
+
+// Metadata is the Kubernetes object metadata of a PrometheusRule.
+type Metadata struct {
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+}
+
+// NewPrometheusRule wraps file in a PrometheusRule resource.
+func NewPrometheusRule(file File, metadata Metadata) PrometheusRule {
+	return PrometheusRule{
+		APIVersion: "monitoring.coreos.com/v1",
+		Kind:       "PrometheusRule",
+		Metadata:   metadata,
+		Spec:       file,
+	}
+}
+
+// duration formats d as a Prometheus duration such as "5m".
+func duration(d time.Duration) string {
+	return model.Duration(d).String()
+}
+
+// number formats a threshold without a trailing ".0".
+func number(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// Generate returns the recording and alerting rules for the given thresholds.
+func Generate(t Thresholds) File {
+	return File{Groups: []Group{
+		{
+			Name: "monit.rules",
+			Rules: []Rule{
+				{
+					Record: "instance:monit_services_failing:sum",
+					Expr:   "sum by (instance) (monit_exporter_service_check != bool 0)",
+				},
+				{
+					Record: "instance:monit_service_block_usage_percent:max",
+					Expr:   "max by (instance) (monit_service_block_usage_percent)",
+				},
+			},
+		},
+		{
+			Name: "monit.alerts",
+			Rules: []Rule{
+				{
+					Alert:  "MonitExporterDown",
+					Expr:   "monit_exporter_up == 0",
+					For:    duration(t.ExporterDownFor),
+					Labels: map[string]string{"severity": "critical"},
+					Annotations: map[string]string{
+						"summary":     "Monit is unreachable from the exporter on {{ $labels.instance }}",
+						"description": "monit-exporter has not been able to fetch the Monit status for " + duration(t.ExporterDownFor) + ".",
+					},
+				},
+				{
+					Alert:  "MonitServiceFailing",
+					Expr:   "monit_service_failure == 1",
+					For:    duration(t.ServiceFailingFor),
+					Labels: map[string]string{"severity": "critical"},
+					Annotations: map[string]string{
+						"summary":     "Monit {{ $labels.service_type }} {{ $labels.service_name }} on {{ $labels.instance }} is failing",
+						"description": "Monit reports the {{ $labels.failure }} failure for {{ $labels.service_name }}.",
+					},
+				},
+				{
+					Alert:  "MonitFilesystemNearlyFull",
+					Expr:   "monit_service_block_usage_percent > " + number(t.FilesystemPercent),
+					For:    duration(t.For),
+					Labels: map[string]string{"severity": "warning"},
+					Annotations: map[string]string{
+						"summary":     "Filesystem {{ $labels.service_name }} on {{ $labels.instance }} is nearly full",
+						"description": "Block usage is {{ $value | printf \"%.1f\" }}% (threshold " + number(t.FilesystemPercent) + "%).",
+					},
+				},
+				{
+					Alert:  "MonitFilesystemInodesNearlyExhausted",
+					Expr:   "monit_service_inode_usage_percent > " + number(t.InodePercent),
+					For:    duration(t.For),
+					Labels: map[string]string{"severity": "warning"},
+					Annotations: map[string]string{
+						"summary":     "Filesystem {{ $labels.service_name }} on {{ $labels.instance }} is running out of inodes",
+						"description": "Inode usage is {{ $value | printf \"%.1f\" }}% (threshold " + number(t.InodePercent) + "%).",
+					},
+				},
+				{
+					Alert:  "MonitCertificateExpiring",
+					Expr:   "monit_service_port_certificate_valid_days < " + number(t.CertificateDays),
+					For:    duration(t.For),
+					Labels: map[string]string{"severity": "warning"},
+					Annotations: map[string]string{
+						"summary":     "Certificate checked by {{ $labels.service_name }} on {{ $labels.instance }} expires soon",
+						"description": "The certificate expires in {{ $value }} days (threshold " + number(t.CertificateDays) + ").",
+					},
+				},
+				{
+					Alert:  "MonitCollectionStale",
+					Expr:   `time() - monit_service_collected_timestamp_seconds{service_monitor_status="1"} > ` + number(t.StaleAfter.Seconds()),
+					For:    duration(t.For),
+					Labels: map[string]string{"severity": "warning"},
+					Annotations: map[string]string{
+						"summary":     "Monit stopped collecting {{ $labels.service_name }} on {{ $labels.instance }}",
+						"description": "The last Monit collection is older than " + duration(t.StaleAfter) + ".",
+					},
+				},
+			},
+		},
+	}}
+}
+
+// metricName matches the exporter metric names referenced by an expression.
+var metricName = regexp.MustCompile(`\bmonit_[a-zA-Z0-9_]+`)
+
+// Validate checks that every exporter metric referenced by the rules is one the
+// Exporter registers, so generated rules cannot silently drift from the metrics.
+func Validate(file File, descriptors []exporter.Descriptor) error {
+	for group := range slices.Values(file.Groups) {
+		for rule := range slices.Values(group.Rules) {
+			for name := range slices.Values(metricName.FindAllString(rule.Expr, -1)) {
+				if !slices.ContainsFunc(descriptors, func(d exporter.Descriptor) bool { return d.Name == name }) {
+					return fmt.Errorf("rule %s%s references unknown metric %s", rule.Alert, rule.Record, name)
+				}
+			}
+		}
+	}
+	return nil
+}
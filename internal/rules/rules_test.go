@@ -0,0 +1,95 @@
+package rules
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ririnto/monit-exporter/internal/config"
+	"github.com/ririnto/monit-exporter/internal/exporter"
+)
+
+// TestGenerate_Thresholds verifies that the thresholds end up in the alert expressions.
+func TestGenerate_Thresholds(t *testing.T) {
+	t.Log("Testing Generate with custom thresholds")
+	thresholds := DefaultThresholds()
+	thresholds.FilesystemPercent = 85.5
+	thresholds.CertificateDays = 30
+	thresholds.StaleAfter = 10 * time.Minute
+	thresholds.ExporterDownFor = 90 * time.Second
+
+	file := Generate(thresholds)
+	alerts := map[string]Rule{}
+	records := 0
+	for _, group := range file.Groups {
+		for _, rule := range group.Rules {
+			if rule.Record != "" {
+				records++
+				continue
+			}
+			alerts[rule.Alert] = rule
+		}
+	}
+	if records == 0 {
+		t.Error("Expected recording rules, got none")
+	}
+
+	tests := []struct {
+		alert string
+		expr  string
+		for_  string
+	}{
+		{"MonitExporterDown", "monit_exporter_up == 0", "1m30s"},
+		{"MonitServiceFailing", "monit_service_failure == 1", "2m"},
+		{"MonitFilesystemNearlyFull", "> 85.5", "15m"},
+		{"MonitFilesystemInodesNearlyExhausted", "> 90", "15m"},
+		{"MonitCertificateExpiring", "< 30", "15m"},
+		{"MonitCollectionStale", "> 600", "15m"},
+	}
+	for _, tt := range tests {
+		rule, ok := alerts[tt.alert]
+		if !ok {
+			t.Errorf("Expected alert %s, got none", tt.alert)
+			continue
+		}
+		if !strings.Contains(rule.Expr, tt.expr) {
+			t.Errorf("Expected %s expression to contain %q, got %q", tt.alert, tt.expr, rule.Expr)
+		}
+		if rule.For != tt.for_ {
+			t.Errorf("Expected %s for %s, got %s", tt.alert, tt.for_, rule.For)
+		}
+		if rule.Labels["severity"] == "" || rule.Annotations["summary"] == "" {
+			t.Errorf("Expected severity and summary on %s, got %+v", tt.alert, rule)
+		}
+	}
+}
+
+// TestValidate verifies that the generated rules only reference metrics the Exporter registers.
+func TestValidate(t *testing.T) {
+	t.Log("Testing Validate against the Exporter descriptors")
+	exp, err := exporter.NewExporter(&config.Config{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := Validate(Generate(DefaultThresholds()), exp.Descriptors()); err != nil {
+		t.Errorf("Expected generated rules to be valid, got %v", err)
+	}
+
+	invalid := File{Groups: []Group{{Name: "test", Rules: []Rule{{Alert: "Typo", Expr: "monit_exporter_upp == 0"}}}}}
+	err = Validate(invalid, exp.Descriptors())
+	if err == nil || !strings.Contains(err.Error(), "monit_exporter_upp") {
+		t.Errorf("Expected unknown metric error, got %v", err)
+	}
+}
+
+// TestNewPrometheusRule verifies the custom resource envelope.
+func TestNewPrometheusRule(t *testing.T) {
+	t.Log("Testing NewPrometheusRule")
+	resource := NewPrometheusRule(File{}, Metadata{Name: "monit", Namespace: "monitoring"})
+	if resource.APIVersion != "monitoring.coreos.com/v1" || resource.Kind != "PrometheusRule" {
+		t.Errorf("Expected a monitoring.coreos.com/v1 PrometheusRule, got %s %s", resource.APIVersion, resource.Kind)
+	}
+	if resource.Metadata.Namespace != "monitoring" {
+		t.Errorf("Expected namespace monitoring, got %s", resource.Metadata.Namespace)
+	}
+}
@@ -0,0 +1,65 @@
+package sink
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/ririnto/monit-exporter/internal/monit"
+	"github.com/sirupsen/logrus"
+)
+
+// GraphiteSink writes snapshots to a Graphite (carbon) plaintext TCP listener,
+// one "path value timestamp" line per service field.
+type GraphiteSink struct {
+	Address string
+	Timeout time.Duration
+	// Path renders the metric path; template values are sanitized so each stays one path component.
+	Path *NameTemplate
+}
+
+// Name identifies the sink in logs.
+func (s *GraphiteSink) Name() string {
+	return "graphite"
+}
+
+// Write sends the snapshot over a new TCP connection.
+func (s *GraphiteSink) Write(ctx context.Context, status monit.Monit) error {
+	dialer := net.Dialer{Timeout: s.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", s.Address)
+	if err != nil {
+		return fmt.Errorf("unable to connect to Graphite: %w", err)
+	}
+	defer func() {
+		if cerr := conn.Close(); cerr != nil {
+			logrus.Warnf("GraphiteSink.Write: failed to close connection: %v", cerr)
+		}
+	}()
+	if s.Timeout > 0 {
+		if err := conn.SetWriteDeadline(time.Now().Add(s.Timeout)); err != nil {
+			return fmt.Errorf("unable to set write deadline: %w", err)
+		}
+	}
+
+	w := bufio.NewWriter(conn)
+	for m := range slices.Values(Measurements(status, time.Now())) {
+		for field := range slices.Values(m.Fields) {
+			data := NameData{Host: m.Host, ServiceName: m.ServiceName, ServiceType: m.ServiceType, Field: field.Name}
+			path, err := s.Path.Render(data.replace(sanitizer.Replace))
+			if err != nil {
+				return fmt.Errorf("unable to render metric path: %w", err)
+			}
+			if _, err := fmt.Fprintf(w, "%s %s %d\n", path, strconv.FormatFloat(field.Value, 'g', -1, 64), m.Time.Unix()); err != nil {
+				return fmt.Errorf("unable to write to Graphite: %w", err)
+			}
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("unable to write to Graphite: %w", err)
+	}
+	return nil
+}
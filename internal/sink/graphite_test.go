@@ -0,0 +1,59 @@
+package sink
+
+import (
+	"context"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGraphiteSink_Write(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen returned error: %v", err)
+	}
+	defer listener.Close()
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		data, _ := io.ReadAll(conn)
+		_ = conn.Close()
+		received <- string(data)
+	}()
+
+	path, _ := ParseNameTemplate("monit.{{.Host}}.{{.ServiceType}}.{{.ServiceName}}.{{.Field}}")
+	s := &GraphiteSink{Address: listener.Addr().String(), Timeout: time.Second, Path: path}
+	if err := s.Write(context.Background(), parseTestStatus(t)); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+
+	data := <-received
+	for _, want := range []string{
+		"monit.web-1.Filesystem.rootfs.block_usage_percent 42.5 1700000000\n",
+		"monit.web-1.Process.nginx_proxy.status 512 ",
+	} {
+		if !strings.Contains(data, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, data)
+		}
+	}
+}
+
+func TestGraphiteSink_ConnectionRefused(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen returned error: %v", err)
+	}
+	address := listener.Addr().String()
+	_ = listener.Close()
+
+	path, _ := ParseNameTemplate("monit.{{.Field}}")
+	s := &GraphiteSink{Address: address, Timeout: time.Second, Path: path}
+	if err := s.Write(context.Background(), parseTestStatus(t)); err == nil {
+		t.Error("Expected an error when Graphite is unreachable, got nil")
+	}
+}
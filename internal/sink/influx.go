@@ -0,0 +1,112 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ririnto/monit-exporter/internal/monit"
+	"github.com/sirupsen/logrus"
+)
+
+// InfluxSink writes snapshots to an InfluxDB HTTP write endpoint in line protocol,
+// one line per service with its fields. URL is the complete write URL, e.g.
+// http://influx:8086/api/v2/write?org=o&bucket=b or http://influx:8086/write?db=monit.
+type InfluxSink struct {
+	URL         string
+	HTTPClient  *http.Client
+	Token       string
+	Username    string
+	Password    string
+	Measurement *NameTemplate
+	Tags        map[string]*NameTemplate
+}
+
+// Name identifies the sink in logs.
+func (s *InfluxSink) Name() string {
+	return "influxdb"
+}
+
+// Write sends the snapshot as a single line protocol batch.
+func (s *InfluxSink) Write(ctx context.Context, status monit.Monit) error {
+	body, err := s.encode(Measurements(status, time.Now()))
+	if err != nil {
+		return err
+	}
+	if len(body) == 0 {
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("unable to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	switch {
+	case s.Token != "":
+		req.Header.Set("Authorization", "Token "+s.Token)
+	case s.Username != "":
+		req.SetBasicAuth(s.Username, s.Password)
+	}
+
+	resp, err := s.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to write to InfluxDB: %w", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			logrus.Warnf("InfluxSink.Write: failed to close response body: %v", cerr)
+		}
+	}()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("InfluxDB returned %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+	return nil
+}
+
+// encode renders measurements as line protocol with nanosecond timestamps.
+func (s *InfluxSink) encode(measurements []Measurement) ([]byte, error) {
+	var buf bytes.Buffer
+	tagNames := slices.Sorted(maps.Keys(s.Tags))
+	for m := range slices.Values(measurements) {
+		data := NameData{Host: m.Host, ServiceName: m.ServiceName, ServiceType: m.ServiceType}
+		name, err := s.Measurement.Render(data)
+		if err != nil {
+			return nil, fmt.Errorf("unable to render measurement name: %w", err)
+		}
+		buf.WriteString(measurementEscaper.Replace(name))
+		for tag := range slices.Values(tagNames) {
+			value, err := s.Tags[tag].Render(data)
+			if err != nil {
+				return nil, fmt.Errorf("unable to render tag %s: %w", tag, err)
+			}
+			if value == "" {
+				continue
+			}
+			fmt.Fprintf(&buf, ",%s=%s", tagEscaper.Replace(tag), tagEscaper.Replace(value))
+		}
+		for i, field := range m.Fields {
+			separator := ","
+			if i == 0 {
+				separator = " "
+			}
+			fmt.Fprintf(&buf, "%s%s=%s", separator, tagEscaper.Replace(field.Name), strconv.FormatFloat(field.Value, 'g', -1, 64))
+		}
+		fmt.Fprintf(&buf, " %d\n", m.Time.UnixNano())
+	}
+	return buf.Bytes(), nil
+}
+
+// Escapers for line protocol measurement names and tag/field keys and tag values.
+var (
+	measurementEscaper = strings.NewReplacer(",", `\,`, " ", `\ `, "\n", `\n`)
+	tagEscaper         = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `, "\n", `\n`)
+)
@@ -0,0 +1,66 @@
+package sink
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestInfluxSink_Write(t *testing.T) {
+	var gotBody, gotAuth, gotQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotBody, gotAuth, gotQuery = string(body), r.Header.Get("Authorization"), r.URL.RawQuery
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	measurement, _ := ParseNameTemplate("monit_{{.ServiceType}}")
+	serviceTag, _ := ParseNameTemplate("{{.ServiceName}}")
+	hostTag, _ := ParseNameTemplate("{{.Host}}")
+	s := &InfluxSink{
+		URL:         server.URL + "/api/v2/write?org=ops&bucket=monit",
+		HTTPClient:  http.DefaultClient,
+		Token:       "secret",
+		Measurement: measurement,
+		Tags:        map[string]*NameTemplate{"service": serviceTag, "host": hostTag},
+	}
+	if err := s.Write(context.Background(), parseTestStatus(t)); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+
+	if gotAuth != "Token secret" {
+		t.Errorf("Expected token auth, got '%s'", gotAuth)
+	}
+	if gotQuery != "org=ops&bucket=monit" {
+		t.Errorf("Expected write query to be kept, got '%s'", gotQuery)
+	}
+	lines := strings.Split(strings.TrimSpace(gotBody), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %q", gotBody)
+	}
+	expected := "monit_Filesystem,host=web-1,service=rootfs status=0,monitor=1,block_usage_bytes=1024,block_total_bytes=2048,block_usage_percent=42.5 1700000000000000000"
+	if lines[0] != expected {
+		t.Errorf("Expected line\n%s\ngot\n%s", expected, lines[0])
+	}
+	if !strings.HasPrefix(lines[1], `monit_Process,host=web-1,service=nginx\ proxy status=512,monitor=1 `) {
+		t.Errorf("Expected escaped tag value, got %s", lines[1])
+	}
+}
+
+func TestInfluxSink_WriteError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bucket not found", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	measurement, _ := ParseNameTemplate("monit")
+	s := &InfluxSink{URL: server.URL, HTTPClient: http.DefaultClient, Measurement: measurement}
+	err := s.Write(context.Background(), parseTestStatus(t))
+	if err == nil || !strings.Contains(err.Error(), "bucket not found") {
+		t.Errorf("Expected error with response body, got %v", err)
+	}
+}
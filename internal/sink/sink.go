@@ -0,0 +1,160 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/ririnto/monit-exporter/internal/monit"
+)
+
+// Sink writes parsed Monit snapshots to an external system.
+type Sink interface {
+	// Name identifies the sink in logs.
+	Name() string
+	// Write delivers the measurements of one snapshot.
+	Write(ctx context.Context, status monit.Monit) error
+}
+
+// WriteAll writes status to every sink concurrently and joins their errors.
+func WriteAll(ctx context.Context, sinks []Sink, status monit.Monit) error {
+	errs := make([]error, len(sinks))
+	var wg sync.WaitGroup
+	for i, s := range sinks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := s.Write(ctx, status); err != nil {
+				errs[i] = fmt.Errorf("%s: %w", s.Name(), err)
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// Field is a named numeric value of a service.
+type Field struct {
+	Name  string
+	Value float64
+}
+
+// Measurement holds the numeric fields of one service in a snapshot.
+type Measurement struct {
+	Host        string
+	ServiceName string
+	ServiceType string
+	Time        time.Time
+	Fields      []Field
+}
+
+// Measurements flattens a snapshot into one measurement per service. Services without a
+// collection time are stamped with now.
+func Measurements(status monit.Monit, now time.Time) []Measurement {
+	measurements := make([]Measurement, 0, len(status.Services))
+	for service := range slices.Values(status.Services) {
+		serviceType, ok := monit.ServiceTypeName(service.Type)
+		if !ok {
+			serviceType = "unknown"
+		}
+		at := now
+		if service.CollectedSec != 0 {
+			at = time.Unix(int64(service.CollectedSec), int64(service.CollectedUsec)*int64(time.Microsecond))
+		}
+
+		fields := []Field{
+			{Name: "status", Value: float64(service.Status)},
+			{Name: "monitor", Value: float64(service.Monitor)},
+		}
+		if service.Block != nil {
+			fields = append(fields,
+				Field{Name: "block_usage_bytes", Value: float64(service.Block.Usage)},
+				Field{Name: "block_total_bytes", Value: float64(service.Block.Total)},
+				Field{Name: "block_usage_percent", Value: float64(service.Block.Percent)},
+			)
+		}
+		if service.Inode != nil {
+			fields = append(fields,
+				Field{Name: "inode_usage", Value: float64(service.Inode.Usage)},
+				Field{Name: "inode_total", Value: float64(service.Inode.Total)},
+				Field{Name: "inode_usage_percent", Value: float64(service.Inode.Percent)},
+			)
+		}
+		if service.Port != nil {
+			fields = append(fields, Field{Name: "port_response_seconds", Value: float64(service.Port.Responsetime)})
+		}
+		if service.System != nil {
+			fields = append(fields,
+				Field{Name: "loadavg_01", Value: float64(service.System.Load.Avg01)},
+				Field{Name: "loadavg_05", Value: float64(service.System.Load.Avg05)},
+				Field{Name: "loadavg_15", Value: float64(service.System.Load.Avg15)},
+				Field{Name: "cpu_user_percent", Value: float64(service.System.CPU.User)},
+				Field{Name: "cpu_system_percent", Value: float64(service.System.CPU.System)},
+				Field{Name: "cpu_wait_percent", Value: float64(service.System.CPU.Wait)},
+				Field{Name: "memory_usage_percent", Value: float64(service.System.Memory.Percent)},
+				Field{Name: "memory_usage_kilobytes", Value: float64(service.System.Memory.Kilobyte)},
+				Field{Name: "swap_usage_percent", Value: float64(service.System.Swap.Percent)},
+				Field{Name: "swap_usage_kilobytes", Value: float64(service.System.Swap.Kilobyte)},
+			)
+		}
+
+		measurements = append(measurements, Measurement{
+			Host:        status.Server.Localhostname,
+			ServiceName: service.Name,
+			ServiceType: serviceType,
+			Time:        at,
+			Fields:      fields,
+		})
+	}
+	return measurements
+}
+
+// NameData is the data available to naming templates.
+type NameData struct {
+	Host        string
+	ServiceName string
+	ServiceType string
+	Field       string
+}
+
+// NameTemplate renders measurement names, tags or metric paths from NameData,
+// e.g. "monit.{{.Host}}.{{.ServiceType}}.{{.ServiceName}}.{{.Field}}".
+type NameTemplate struct {
+	tmpl *template.Template
+}
+
+// ParseNameTemplate parses and validates a naming template.
+func ParseNameTemplate(text string) (*NameTemplate, error) {
+	tmpl, err := template.New("name").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid naming template '%s': %w", text, err)
+	}
+	t := &NameTemplate{tmpl: tmpl}
+	if _, err := t.Render(NameData{}); err != nil {
+		return nil, fmt.Errorf("invalid naming template '%s': %w", text, err)
+	}
+	return t, nil
+}
+
+// Render executes the template.
+func (t *NameTemplate) Render(data NameData) (string, error) {
+	var buf bytes.Buffer
+	if err := t.tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// replace returns data with every value passed through fn.
+func (d NameData) replace(fn func(string) string) NameData {
+	return NameData{Host: fn(d.Host), ServiceName: fn(d.ServiceName), ServiceType: fn(d.ServiceType), Field: fn(d.Field)}
+}
+
+// sanitizer replaces characters that are not safe inside a single metric path component.
+var sanitizer = strings.NewReplacer(".", "_", " ", "_", "/", "_", "\t", "_", "\n", "_")
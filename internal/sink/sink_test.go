@@ -0,0 +1,92 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ririnto/monit-exporter/internal/monit"
+)
+
+const testXML = `<?xml version="1.0"?>
+<monit>
+  <server><localhostname>web-1</localhostname></server>
+  <service type="0"><name>rootfs</name><collected_sec>1700000000</collected_sec><status>0</status><monitor>1</monitor>
+    <block><percent>42.5</percent><usage>1024</usage><total>2048</total></block></service>
+  <service type="3"><name>nginx proxy</name><status>512</status><monitor>1</monitor></service>
+</monit>`
+
+func parseTestStatus(t *testing.T) monit.Monit {
+	t.Helper()
+	status, err := monit.ParseMonitStatus([]byte(testXML))
+	if err != nil {
+		t.Fatalf("ParseMonitStatus returned error: %v", err)
+	}
+	return status
+}
+
+func TestMeasurements(t *testing.T) {
+	now := time.Unix(1800000000, 0)
+	measurements := Measurements(parseTestStatus(t), now)
+	if len(measurements) != 2 {
+		t.Fatalf("Expected 2 measurements, got %d", len(measurements))
+	}
+
+	rootfs := measurements[0]
+	if rootfs.Host != "web-1" || rootfs.ServiceType != "Filesystem" || !rootfs.Time.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("Unexpected rootfs measurement %+v", rootfs)
+	}
+	if len(rootfs.Fields) != 5 || rootfs.Fields[4] != (Field{Name: "block_usage_percent", Value: 42.5}) {
+		t.Errorf("Unexpected rootfs fields %+v", rootfs.Fields)
+	}
+	nginx := measurements[1]
+	if nginx.ServiceType != "Process" || !nginx.Time.Equal(now) || nginx.Fields[0].Value != 512 {
+		t.Errorf("Unexpected nginx measurement %+v", nginx)
+	}
+}
+
+func TestParseNameTemplate(t *testing.T) {
+	tmpl, err := ParseNameTemplate("monit.{{.Host}}.{{.ServiceName}}")
+	if err != nil {
+		t.Fatalf("ParseNameTemplate returned error: %v", err)
+	}
+	name, err := tmpl.Render(NameData{Host: "web-1", ServiceName: "rootfs"})
+	if err != nil || name != "monit.web-1.rootfs" {
+		t.Errorf("Expected 'monit.web-1.rootfs', got '%s' (err=%v)", name, err)
+	}
+
+	for _, invalid := range []string{"{{.Host", "{{.Unknown}}"} {
+		if _, err := ParseNameTemplate(invalid); err == nil {
+			t.Errorf("Expected an error for template '%s', got nil", invalid)
+		}
+	}
+}
+
+// fakeSink records writes and optionally fails.
+type fakeSink struct {
+	name   string
+	err    error
+	writes int
+}
+
+func (s *fakeSink) Name() string { return s.name }
+
+func (s *fakeSink) Write(context.Context, monit.Monit) error {
+	s.writes++
+	return s.err
+}
+
+func TestWriteAll(t *testing.T) {
+	ok := &fakeSink{name: "ok"}
+	failing := &fakeSink{name: "broken", err: errors.New("boom")}
+
+	err := WriteAll(context.Background(), []Sink{ok, failing}, parseTestStatus(t))
+	if err == nil || !strings.Contains(err.Error(), "broken: boom") {
+		t.Errorf("Expected error naming the failing sink, got %v", err)
+	}
+	if ok.writes != 1 || failing.writes != 1 {
+		t.Errorf("Expected every sink to be written once, got %d and %d", ok.writes, failing.writes)
+	}
+}
@@ -0,0 +1,138 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ririnto/monit-exporter/internal/monit"
+	"github.com/sirupsen/logrus"
+)
+
+// StatsD protocol flavors.
+const (
+	FlavorStatsD    = "statsd"
+	FlavorDogStatsD = "dogstatsd"
+)
+
+// defaultMaxPacketSize keeps datagrams below a typical Ethernet MTU.
+const defaultMaxPacketSize = 1432
+
+// ErrInvalidFlavor is returned for an unsupported StatsD flavor.
+var ErrInvalidFlavor = errors.New("invalid StatsD flavor")
+
+// StatsDSink emits every service field as a gauge over UDP. The DogStatsD flavor tags
+// each gauge with service_name, service_type and host; plain StatsD has no tags, so
+// the name template should include the service.
+type StatsDSink struct {
+	Address string
+	Flavor  string
+	// Metric renders the gauge name; template values are sanitized for the StatsD protocol.
+	Metric *NameTemplate
+	// Tags are constant DogStatsD tags such as "env:prod".
+	Tags          []string
+	MaxPacketSize int
+}
+
+// ValidateFlavor checks that flavor is a supported StatsD flavor.
+func ValidateFlavor(flavor string) error {
+	if flavor != FlavorStatsD && flavor != FlavorDogStatsD {
+		return fmt.Errorf("%w: '%s' (expected %s or %s)", ErrInvalidFlavor, flavor, FlavorStatsD, FlavorDogStatsD)
+	}
+	return nil
+}
+
+// Name identifies the sink in logs.
+func (s *StatsDSink) Name() string {
+	return s.Flavor
+}
+
+// Write sends the gauges of the snapshot, batching lines into datagrams.
+func (s *StatsDSink) Write(ctx context.Context, status monit.Monit) error {
+	if err := ValidateFlavor(s.Flavor); err != nil {
+		return err
+	}
+	lines, err := s.encode(Measurements(status, time.Now()))
+	if err != nil {
+		return err
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "udp", s.Address)
+	if err != nil {
+		return fmt.Errorf("unable to connect to StatsD: %w", err)
+	}
+	defer func() {
+		if cerr := conn.Close(); cerr != nil {
+			logrus.Warnf("StatsDSink.Write: failed to close connection: %v", cerr)
+		}
+	}()
+
+	maxSize := s.MaxPacketSize
+	if maxSize <= 0 {
+		maxSize = defaultMaxPacketSize
+	}
+	var packet []byte
+	for line := range slices.Values(lines) {
+		if 0 < len(packet) && maxSize < len(packet)+1+len(line) {
+			if _, err := conn.Write(packet); err != nil {
+				return fmt.Errorf("unable to write to StatsD: %w", err)
+			}
+			packet = packet[:0]
+		}
+		if 0 < len(packet) {
+			packet = append(packet, '\n')
+		}
+		packet = append(packet, line...)
+	}
+	if 0 < len(packet) {
+		if _, err := conn.Write(packet); err != nil {
+			return fmt.Errorf("unable to write to StatsD: %w", err)
+		}
+	}
+	return nil
+}
+
+// encode renders one gauge line per service field.
+func (s *StatsDSink) encode(measurements []Measurement) ([]string, error) {
+	var lines []string
+	for m := range slices.Values(measurements) {
+		var tags string
+		if s.Flavor == FlavorDogStatsD {
+			all := append(slices.Clone(s.Tags),
+				"service_name:"+tagSanitizer.Replace(m.ServiceName),
+				"service_type:"+tagSanitizer.Replace(m.ServiceType),
+			)
+			if m.Host != "" {
+				all = append(all, "host:"+tagSanitizer.Replace(m.Host))
+			}
+			tags = "|#" + strings.Join(all, ",")
+		}
+		for field := range slices.Values(m.Fields) {
+			data := NameData{Host: m.Host, ServiceName: m.ServiceName, ServiceType: m.ServiceType, Field: field.Name}
+			name, err := s.Metric.Render(data.replace(func(v string) string {
+				return nameSanitizer.Replace(sanitizer.Replace(v))
+			}))
+			if err != nil {
+				return nil, fmt.Errorf("unable to render gauge name: %w", err)
+			}
+			// A leading sign makes StatsD apply a delta, so negative gauges are reset to zero first.
+			if field.Value < 0 {
+				lines = append(lines, name+":0|g"+tags)
+			}
+			lines = append(lines, name+":"+strconv.FormatFloat(field.Value, 'g', -1, 64)+"|g"+tags)
+		}
+	}
+	return lines, nil
+}
+
+// Sanitizers for characters reserved by the StatsD and DogStatsD protocols.
+var (
+	nameSanitizer = strings.NewReplacer(":", "_", "|", "_", "@", "_", "#", "_")
+	tagSanitizer  = strings.NewReplacer(",", "_", "|", "_", "#", "_", "\n", "_")
+)
@@ -0,0 +1,90 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// listenUDP returns a UDP listener and a function reading all datagrams received so far.
+func listenUDP(t *testing.T) (*net.UDPConn, func() []string) {
+	t.Helper()
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("ListenUDP returned error: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn, func() []string {
+		var packets []string
+		buf := make([]byte, 65535)
+		for {
+			_ = conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+			n, err := conn.Read(buf)
+			if err != nil {
+				return packets
+			}
+			packets = append(packets, string(buf[:n]))
+		}
+	}
+}
+
+func TestStatsDSink_DogStatsD(t *testing.T) {
+	conn, read := listenUDP(t)
+	name, _ := ParseNameTemplate("monit.{{.Field}}")
+	s := &StatsDSink{Address: conn.LocalAddr().String(), Flavor: FlavorDogStatsD, Metric: name, Tags: []string{"env:prod"}}
+	if err := s.Write(context.Background(), parseTestStatus(t)); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+
+	data := strings.Join(read(), "\n")
+	for _, want := range []string{
+		"monit.block_usage_percent:42.5|g|#env:prod,service_name:rootfs,service_type:Filesystem,host:web-1",
+		"monit.status:512|g|#env:prod,service_name:nginx proxy,service_type:Process,host:web-1",
+	} {
+		if !strings.Contains(data, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, data)
+		}
+	}
+}
+
+func TestStatsDSink_PlainBatching(t *testing.T) {
+	conn, read := listenUDP(t)
+	name, _ := ParseNameTemplate("monit.{{.ServiceName}}.{{.Field}}")
+	s := &StatsDSink{Address: conn.LocalAddr().String(), Flavor: FlavorStatsD, Metric: name, MaxPacketSize: 64}
+	if err := s.Write(context.Background(), parseTestStatus(t)); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+
+	packets := read()
+	if len(packets) < 2 {
+		t.Fatalf("Expected the lines to be split into several packets, got %d", len(packets))
+	}
+	var lines []string
+	for _, packet := range packets {
+		if 64 < len(packet) {
+			t.Errorf("Packet exceeds the maximum size: %q", packet)
+		}
+		lines = append(lines, strings.Split(packet, "\n")...)
+	}
+	if len(lines) != 7 {
+		t.Errorf("Expected 7 gauge lines, got %d: %v", len(lines), lines)
+	}
+	if !strings.Contains(strings.Join(lines, "\n"), "monit.nginx_proxy.status:512|g") {
+		t.Errorf("Expected sanitized plain gauge, got %v", lines)
+	}
+	if strings.Contains(strings.Join(lines, "\n"), "|#") {
+		t.Error("Expected no tags in plain StatsD output")
+	}
+}
+
+func TestValidateFlavor(t *testing.T) {
+	if err := ValidateFlavor("graphite"); !errors.Is(err, ErrInvalidFlavor) {
+		t.Errorf("Expected ErrInvalidFlavor, got %v", err)
+	}
+	if err := ValidateFlavor(FlavorDogStatsD); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}